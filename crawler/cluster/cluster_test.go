@@ -0,0 +1,319 @@
+package cluster_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/cluster"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	// coordinatorEnv turns the test binary into a worker of the coordinator by the URL.
+	coordinatorEnv = "GORECS_TEST_COORDINATOR"
+	// crashEnv makes the worker process exit right after it leases links.
+	crashEnv = "GORECS_TEST_CRASH"
+)
+
+func TestMain(m *testing.M) {
+	coordinator := os.Getenv(coordinatorEnv)
+	if coordinator == "" {
+		os.Exit(m.Run())
+	}
+
+	if os.Getenv(crashEnv) != "" {
+		resp, err := http.Post(coordinator+cluster.LeasePath, "application/json", strings.NewReader(`{"worker":"crashed"}`))
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		os.Exit(2)
+	}
+
+	w := cluster.NewWorker(coordinator, cluster.WithPollInterval(10*time.Millisecond))
+	if err := w.Run(context.Background()); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// startWorkers runs the test binary as worker processes of the coordinator.
+func startWorkers(t *testing.T, coordinator string, n int, env ...string) []*exec.Cmd {
+	t.Helper()
+
+	cmds := make([]*exec.Cmd, 0, n)
+	for i := 0; i < n; i++ {
+		cmd := exec.Command(os.Args[0])
+		cmd.Env = append(os.Environ(), append(env, coordinatorEnv+"="+coordinator)...)
+		cmd.Stderr = &bytes.Buffer{}
+		require.NoError(t, cmd.Start())
+		cmds = append(cmds, cmd)
+	}
+
+	return cmds
+}
+
+// treeSite serves a binary tree of pages: /1 links to /2 and /3, /2 links to /4 and /5 and so on.
+// It records how many times every page is fetched and the maximal number of concurrent requests.
+type treeSite struct {
+	*httptest.Server
+
+	inflight    atomic.Int32
+	maxInflight atomic.Int32
+	mutex       sync.Mutex
+	fetched     map[string]int
+	links       []string
+}
+
+func newTreeSite(links ...string) *treeSite {
+	s := &treeSite{fetched: map[string]int{}, links: links}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inflight := s.inflight.Add(1)
+		defer s.inflight.Add(-1)
+		for current := s.maxInflight.Load(); inflight > current; current = s.maxInflight.Load() {
+			s.maxInflight.CompareAndSwap(current, inflight)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		s.mutex.Lock()
+		s.fetched[r.URL.Path]++
+		s.mutex.Unlock()
+
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		_, _ = fmt.Fprintf(w, `<html><body><p>page %d</p><a href="/%d">left</a><a href="/%d">right</a>`, n, 2*n, 2*n+1)
+		if n == 1 {
+			for _, link := range s.links {
+				_, _ = fmt.Fprintf(w, `<a href="%s">other site</a>`, link)
+			}
+		}
+		_, _ = fmt.Fprint(w, `</body></html>`)
+	}))
+
+	return s
+}
+
+func TestCoordinator_Workers(t *testing.T) {
+	// given
+	b := newTreeSite()
+	defer b.Close()
+	a := newTreeSite(b.URL + "/1")
+	defer a.Close()
+
+	c := cluster.NewCoordinator(cluster.WithMaxDepth(3), cluster.WithBatchSize(2))
+	coordinator := httptest.NewServer(c)
+	defer coordinator.Close()
+	c.Seed(a.URL + "/1")
+
+	// when
+	cmds := startWorkers(t, coordinator.URL, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, c.Wait(ctx))
+
+	// expected
+	for _, cmd := range cmds {
+		assert.NoError(t, cmd.Wait(), cmd.Stderr)
+	}
+
+	pages := c.Pages()
+	// a: 4 levels of the tree, b: 3 levels as it's linked from the first level of a.
+	assert.Len(t, pages, 15+7)
+	assert.Equal(t, []string{"right"}, pages[a.URL+"/3"].Fields["anchors"])
+	assert.Contains(t, c.LinkGraph().Links(a.URL+"/1"), b.URL+"/1")
+
+	for _, site := range []*treeSite{a, b} {
+		assert.Equal(t, int32(1), site.maxInflight.Load(), "host must be fetched by one worker at a time")
+		for path, n := range site.fetched {
+			assert.Equal(t, 1, n, "page %s must be fetched once", path)
+		}
+	}
+
+	status := c.Status()
+	assert.True(t, status.Done)
+	assert.Equal(t, 22, status.Pages)
+	assert.Zero(t, status.Queued)
+	assert.Zero(t, status.Leased)
+}
+
+func TestCoordinator_ReclaimLease(t *testing.T) {
+	t.Run("should hand out links of a crashed worker again", func(t *testing.T) {
+		// given
+		site := newTreeSite()
+		defer site.Close()
+
+		c := cluster.NewCoordinator(cluster.WithMaxDepth(2), cluster.WithLeaseTimeout(time.Second))
+		coordinator := httptest.NewServer(c)
+		defer coordinator.Close()
+		c.Seed(site.URL + "/1")
+
+		crashed := startWorkers(t, coordinator.URL, 1, crashEnv+"=1")[0]
+		require.Error(t, crashed.Wait())
+		assert.Equal(t, 1, c.Status().Leased)
+
+		// when
+		startWorkers(t, coordinator.URL, 1)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		require.NoError(t, c.Wait(ctx))
+
+		// expected
+		assert.Len(t, c.Pages(), 7)
+		assert.Equal(t, 1, c.Status().Reclaimed)
+	})
+
+	t.Run("should reject results of an expired lease", func(t *testing.T) {
+		// given
+		c := cluster.NewCoordinator(cluster.WithLeaseTimeout(10 * time.Millisecond))
+		c.Seed("https://example.com/")
+		l, ok := c.Lease("slow")
+		require.True(t, ok)
+
+		// when
+		time.Sleep(20 * time.Millisecond)
+		err := c.Complete(cluster.Report{LeaseID: l.ID})
+
+		// expected
+		require.ErrorIs(t, err, cluster.ErrUnknownLease)
+		again, ok := c.Lease("other")
+		require.True(t, ok)
+		assert.Equal(t, l.Links, again.Links)
+	})
+}
+
+func TestCoordinator_Lease(t *testing.T) {
+	t.Run("should lease a host to one worker at a time", func(t *testing.T) {
+		// given
+		c := cluster.NewCoordinator(cluster.WithBatchSize(1))
+		c.Seed("https://a.com/1", "https://a.com/2", "https://b.com/1")
+
+		// when
+		first, _ := c.Lease("w1")
+		second, _ := c.Lease("w2")
+		_, ok := c.Lease("w3")
+
+		// expected
+		assert.Equal(t, "a.com", first.Host)
+		assert.Equal(t, "b.com", second.Host)
+		assert.False(t, ok)
+
+		require.NoError(t, c.Complete(cluster.Report{LeaseID: first.ID}))
+		third, ok := c.Lease("w3")
+		require.True(t, ok, "not reported links must be handed out again")
+		assert.Equal(t, "https://a.com/1", third.Links[0].URL)
+	})
+
+	t.Run("should finish the crawl when the page budget is exhausted", func(t *testing.T) {
+		// given
+		c := cluster.NewCoordinator(cluster.WithMaxPages(2))
+		c.Seed("https://a.com/1", "https://a.com/2", "https://a.com/3")
+
+		// when
+		l, ok := c.Lease("w1")
+		require.True(t, ok)
+		require.Len(t, l.Links, 2)
+		require.NoError(t, c.Complete(cluster.Report{LeaseID: l.ID, Results: []cluster.Result{
+			{Link: l.Links[0], Error: "not found"},
+			{Link: l.Links[1]},
+		}}))
+
+		// expected
+		status := c.Status()
+		assert.True(t, status.Done)
+		assert.Equal(t, 1, status.Pages)
+		assert.Equal(t, 1, status.Failed)
+		assert.Zero(t, status.Queued)
+	})
+}
+
+func TestWorker_Run(t *testing.T) {
+	// given
+	site := newTreeSite()
+	defer site.Close()
+
+	c := cluster.NewCoordinator(cluster.WithMaxDepth(3), cluster.WithBatchSize(1))
+	coordinator := httptest.NewServer(c)
+	defer coordinator.Close()
+	c.Seed(site.URL + "/1")
+
+	crawler := web.NewCrawler()
+	w := cluster.NewWorker(coordinator.URL, cluster.WithCrawler(crawler), cluster.WithPollInterval(10*time.Millisecond))
+
+	// when
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, w.Run(ctx))
+
+	// expected
+	assert.Len(t, c.Pages(), 15)
+	assert.Empty(t, crawler.LinkGraph().Edges(), "the worker must not keep links between leases")
+	for i := 1; i <= 31; i++ {
+		assert.Empty(t, crawler.AnchorTexts(fmt.Sprintf("%s/%d", site.URL, i)), "the worker must not keep anchor texts between leases")
+	}
+}
+
+func TestWorker_RunHostDelay(t *testing.T) {
+	// given
+	mutex := sync.Mutex{}
+	requests := make([]time.Time, 0)
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, time.Now())
+		mutex.Unlock()
+		_, _ = fmt.Fprint(w, `<html><body><p>page</p></body></html>`)
+	}))
+	defer site.Close()
+
+	delay := 50 * time.Millisecond
+	c := cluster.NewCoordinator(cluster.WithMaxDepth(0), cluster.WithBatchSize(3), cluster.WithHostDelay(delay))
+	coordinator := httptest.NewServer(c)
+	defer coordinator.Close()
+	c.Seed(site.URL+"/1", site.URL+"/2", site.URL+"/3")
+	w := cluster.NewWorker(coordinator.URL, cluster.WithPollInterval(10*time.Millisecond))
+
+	// when
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, w.Run(ctx))
+
+	// expected
+	require.Len(t, requests, 3)
+	for i := 1; i < len(requests); i++ {
+		assert.GreaterOrEqual(t, requests[i].Sub(requests[i-1]), delay)
+	}
+}
+
+func TestWorker_RunTrapRules(t *testing.T) {
+	// given
+	site := newTreeSite("/session?sid=1", "/a/a/a/a")
+	defer site.Close()
+
+	o := web.NewProgressObserver(nil)
+	c := cluster.NewCoordinator(cluster.WithMaxDepth(1), cluster.WithObserver(o))
+	coordinator := httptest.NewServer(c)
+	defer coordinator.Close()
+	c.Seed(site.URL + "/1")
+	w := cluster.NewWorker(coordinator.URL, cluster.WithPollInterval(10*time.Millisecond))
+
+	// when
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	require.NoError(t, w.Run(ctx))
+
+	// expected
+	assert.Len(t, c.Pages(), 3)
+	assert.NotContains(t, c.LinkGraph().Links(site.URL+"/1"), site.URL+"/session?sid=1")
+	assert.Equal(t, 1, o.Progress().SkipReasons[web.SkipReasonTrap+web.TrapSessionID])
+	assert.Equal(t, 1, o.Progress().SkipReasons[web.SkipReasonTrap+web.TrapRepeatedSegments])
+}
@@ -0,0 +1,506 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Paths of the Coordinator HTTP API.
+const (
+	LeasePath    = "/lease"
+	CompletePath = "/complete"
+	StatusPath   = "/status"
+)
+
+const (
+	DefaultLeaseTimeout = time.Minute
+	DefaultBatchSize    = 10
+)
+
+var ErrUnknownLease = errors.New("unknown or expired lease")
+
+// Lease is a batch of links of a single host handed out to a worker.
+// Links must be reported by Coordinator.Complete before the lease expires, otherwise they are handed out again.
+type Lease struct {
+	ID      string     `json:"id"`
+	Host    string     `json:"host"`
+	Links   []web.Link `json:"links"`
+	Expires time.Time  `json:"expires"`
+	// Delay is the minimal time between fetches of the links.
+	Delay time.Duration `json:"delay"`
+}
+
+// Result is a fetched link of a Lease.
+type Result struct {
+	Link web.Link `json:"link"`
+	Page web.Page `json:"page"`
+	// Links are links found on the page, Quarantined are the ones which look like crawler traps, by the tripped rule.
+	Links       []web.Link        `json:"links"`
+	Quarantined map[string]string `json:"quarantined,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// Report is sent by a worker when all links of the Lease are fetched.
+type Report struct {
+	LeaseID string   `json:"lease_id"`
+	Results []Result `json:"results"`
+}
+
+// Status is a summary of a distributed crawl.
+type Status struct {
+	Queued int `json:"queued"`
+	Leased int `json:"leased"`
+	Pages  int `json:"pages"`
+	Failed int `json:"failed"`
+	// Reclaimed is a number of expired leases whose links were handed out again.
+	Reclaimed int  `json:"reclaimed"`
+	Done      bool `json:"done"`
+}
+
+type leaseRequest struct {
+	Worker string `json:"worker"`
+}
+
+type lease struct {
+	Lease
+	worker string
+}
+
+// Coordinator owns the frontier and the visited set of a distributed crawl and hands out links to workers.
+//
+// Links are partitioned by host and a host is leased to one worker at a time,
+// so workers never fetch the same host concurrently.
+// Coordinator serves its API over HTTP, see LeasePath, CompletePath and StatusPath.
+type Coordinator struct {
+	mutex *sync.Mutex
+	mux   *http.ServeMux
+
+	queues    map[string][]web.Link
+	hosts     []string
+	next      int
+	visited   map[string]bool
+	leases    map[string]*lease
+	leased    map[string]bool
+	lastLease map[string]time.Time
+	leaseID   int
+
+	pages      map[string]web.Page
+	failed     map[string]string
+	anchors    map[string][]string
+	graph      *web.LinkGraph
+	dispatched int
+	reclaimed  int
+
+	seeds []string
+	start time.Time
+	done  chan struct{}
+
+	leaseTimeout time.Duration
+	hostDelay    time.Duration
+	batchSize    int
+	maxDepth     int
+	maxPages     int
+	observer     web.Observer
+}
+
+// CoordinatorOption configures a Coordinator.
+type CoordinatorOption func(c *Coordinator)
+
+// WithLeaseTimeout sets the time a worker has to report a Lease.
+func WithLeaseTimeout(timeout time.Duration) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.leaseTimeout = timeout
+	}
+}
+
+// WithHostDelay sets the minimal time between leases of the same host and between fetches of links of a Lease.
+func WithHostDelay(delay time.Duration) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.hostDelay = delay
+	}
+}
+
+// WithBatchSize sets the maximal number of links in a Lease.
+func WithBatchSize(size int) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.batchSize = max(size, 1)
+	}
+}
+
+// WithMaxDepth sets a number of links from the seeds after which links aren't followed.
+func WithMaxDepth(depth int) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.maxDepth = depth
+	}
+}
+
+// WithMaxPages sets a page budget of the crawl, zero means no limit.
+func WithMaxPages(pages int) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.maxPages = pages
+	}
+}
+
+// WithObserver sets the Observer notified about queued, failed and skipped links and the end of the crawl.
+func WithObserver(observer web.Observer) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.observer = observer
+	}
+}
+
+func NewCoordinator(options ...CoordinatorOption) *Coordinator {
+	c := &Coordinator{
+		mutex:        &sync.Mutex{},
+		mux:          http.NewServeMux(),
+		queues:       map[string][]web.Link{},
+		visited:      map[string]bool{},
+		leases:       map[string]*lease{},
+		leased:       map[string]bool{},
+		lastLease:    map[string]time.Time{},
+		pages:        map[string]web.Page{},
+		failed:       map[string]string{},
+		anchors:      map[string][]string{},
+		graph:        web.NewLinkGraph(),
+		done:         make(chan struct{}),
+		leaseTimeout: DefaultLeaseTimeout,
+		batchSize:    DefaultBatchSize,
+		maxDepth:     web.DefaultMaxDepth,
+		observer:     web.NopObserver{},
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	c.mux.HandleFunc("POST "+LeasePath, c.handleLease)
+	c.mux.HandleFunc("POST "+CompletePath, c.handleComplete)
+	c.mux.HandleFunc("GET "+StatusPath, c.handleStatus)
+
+	return c
+}
+
+// Seed adds start URLs of the crawl.
+func (c *Coordinator) Seed(urls ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.seeds) == 0 {
+		c.start = time.Now()
+	}
+	for _, u := range urls {
+		c.seeds = append(c.seeds, u)
+		c.enqueue(web.Link{URL: u})
+	}
+	c.checkDone()
+}
+
+// Lease hands out links of a host which isn't leased by other workers.
+// False is returned when there are no links to hand out at the moment.
+func (c *Coordinator) Lease(worker string) (Lease, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	c.reclaim(now)
+
+	for i := range c.hosts {
+		host := c.hosts[(c.next+i)%len(c.hosts)]
+		if c.leased[host] || len(c.queues[host]) == 0 || now.Sub(c.lastLease[host]) < c.hostDelay {
+			continue
+		}
+
+		size := min(c.batchSize, len(c.queues[host]))
+		if c.maxPages != 0 {
+			size = min(size, c.maxPages-c.dispatched)
+		}
+		if size <= 0 {
+			return Lease{}, false
+		}
+
+		c.leaseID++
+		l := &lease{
+			Lease: Lease{
+				ID:      strconv.Itoa(c.leaseID),
+				Host:    host,
+				Links:   c.queues[host][:size:size],
+				Expires: now.Add(c.leaseTimeout),
+				Delay:   c.hostDelay,
+			},
+			worker: worker,
+		}
+		c.queues[host] = c.queues[host][size:]
+		c.leases[l.ID] = l
+		c.leased[host] = true
+		c.lastLease[host] = now
+		c.dispatched += size
+		c.next = (c.next + i + 1) % len(c.hosts)
+
+		return l.Lease, true
+	}
+
+	return Lease{}, false
+}
+
+// Complete stores results of the Lease and queues links found on fetched pages.
+// ErrUnknownLease is returned when the lease is expired, its links are handed out again in this case.
+func (c *Coordinator) Complete(report Report) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.reclaim(time.Now())
+	l, ok := c.leases[report.LeaseID]
+	if !ok {
+		return ErrUnknownLease
+	}
+	delete(c.leases, l.ID)
+	delete(c.leased, l.Host)
+
+	leased := make(map[string]web.Link, len(l.Links))
+	for _, link := range l.Links {
+		leased[link.URL] = link
+	}
+
+	for _, r := range report.Results {
+		link, ok := leased[r.Link.URL]
+		if !ok {
+			continue
+		}
+		delete(leased, link.URL)
+
+		if r.Error != "" {
+			c.failed[link.URL] = r.Error
+			c.observer.OnError(link.URL, errors.New(r.Error))
+			continue
+		}
+
+		c.pages[link.URL] = r.Page
+		for found, rule := range r.Quarantined {
+			c.observer.OnSkipped(found, web.SkipReasonTrap+rule)
+		}
+		for _, found := range r.Links {
+			c.graph.AddLink(link.URL, found.URL)
+			if found.AnchorText != "" {
+				c.anchors[found.URL] = append(c.anchors[found.URL], found.AnchorText)
+			}
+			if link.Depth < c.maxDepth {
+				found.Depth = link.Depth + 1
+				c.enqueue(found)
+			}
+		}
+	}
+
+	// links the worker didn't report are fetched again.
+	for _, link := range l.Links {
+		if _, ok := leased[link.URL]; ok {
+			c.requeue(link)
+		}
+	}
+	c.checkDone()
+
+	return nil
+}
+
+// Status returns a summary of the crawl.
+func (c *Coordinator) Status() Status {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.reclaim(time.Now())
+
+	return c.status()
+}
+
+// Pages returns fetched pages by URL with texts of links to them in web.FieldAnchors.
+func (c *Coordinator) Pages() map[string]web.Page {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	pages := make(map[string]web.Page, len(c.pages))
+	for link, page := range c.pages {
+		if anchors := c.anchors[link]; len(anchors) != 0 {
+			page.Fields = maps.Clone(page.Fields)
+			if page.Fields == nil {
+				page.Fields = map[string][]string{}
+			}
+			page.Fields[web.FieldAnchors] = append([]string(nil), anchors...)
+		}
+		pages[link] = page
+	}
+
+	return pages
+}
+
+// LinkGraph returns links between fetched pages.
+func (c *Coordinator) LinkGraph() *web.LinkGraph {
+	return c.graph
+}
+
+// Done is closed when all links are fetched or the page budget is exhausted.
+func (c *Coordinator) Done() <-chan struct{} {
+	return c.done
+}
+
+// Wait blocks until the crawl is done.
+// Expired leases are reclaimed while waiting, so the crawl is finished even when all workers holding leases crashed.
+func (c *Coordinator) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(max(c.leaseTimeout/2, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.mutex.Lock()
+			c.reclaim(time.Now())
+			c.mutex.Unlock()
+		}
+	}
+}
+
+func (c *Coordinator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mux.ServeHTTP(w, r)
+}
+
+func (c *Coordinator) handleLease(w http.ResponseWriter, r *http.Request) {
+	var req leaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	l, ok := c.Lease(req.Worker)
+	if !ok {
+		select {
+		case <-c.done:
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	writeJSON(w, l)
+}
+
+func (c *Coordinator) handleComplete(w http.ResponseWriter, r *http.Request) {
+	var report Report
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Complete(report); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Coordinator) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, c.Status())
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// enqueue adds a link which isn't visited yet to the queue of its host.
+func (c *Coordinator) enqueue(link web.Link) {
+	if c.visited[link.URL] {
+		return
+	}
+	c.visited[link.URL] = true
+
+	host := hostOf(link.URL)
+	if _, ok := c.queues[host]; !ok {
+		c.hosts = append(c.hosts, host)
+	}
+	c.queues[host] = append(c.queues[host], link)
+	c.observer.OnQueued(link.URL, link.Depth)
+}
+
+// requeue puts a leased link back to the head of its host queue.
+func (c *Coordinator) requeue(link web.Link) {
+	host := hostOf(link.URL)
+	c.queues[host] = append([]web.Link{link}, c.queues[host]...)
+	c.dispatched--
+}
+
+// reclaim hands out links of expired leases again.
+func (c *Coordinator) reclaim(now time.Time) {
+	for id, l := range c.leases {
+		if now.Before(l.Expires) {
+			continue
+		}
+
+		delete(c.leases, id)
+		delete(c.leased, l.Host)
+		for i := len(l.Links) - 1; i >= 0; i-- {
+			c.requeue(l.Links[i])
+		}
+		c.reclaimed++
+	}
+	c.checkDone()
+}
+
+func (c *Coordinator) status() Status {
+	s := Status{
+		Leased:    len(c.leases),
+		Pages:     len(c.pages),
+		Failed:    len(c.failed),
+		Reclaimed: c.reclaimed,
+	}
+	for _, queue := range c.queues {
+		s.Queued += len(queue)
+	}
+	select {
+	case <-c.done:
+		s.Done = true
+	default:
+	}
+
+	return s
+}
+
+// checkDone finishes the crawl when nothing is leased and there is nothing left to hand out.
+func (c *Coordinator) checkDone() {
+	s := c.status()
+	if s.Done || len(c.seeds) == 0 || s.Leased != 0 {
+		return
+	}
+	budget := c.maxPages != 0 && c.dispatched >= c.maxPages
+	if s.Queued != 0 && !budget {
+		return
+	}
+
+	for _, host := range c.hosts {
+		for _, link := range c.queues[host] {
+			c.observer.OnSkipped(link.URL, web.SkipReasonPageBudget)
+		}
+		c.queues[host] = nil
+	}
+	close(c.done)
+	c.observer.OnDone(c.seeds[0], len(c.pages), time.Since(c.start))
+}
+
+// hostOf returns the host with the port of the link, links of the same host are fetched by one worker at a time.
+func hostOf(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return u.Host
+}
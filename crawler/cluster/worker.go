@@ -0,0 +1,206 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"net/http"
+	"os"
+	"time"
+)
+
+const DefaultPollInterval = time.Second
+
+// Worker fetches links leased from a Coordinator and reports results back.
+type Worker struct {
+	id           string
+	coordinator  string
+	crawler      *web.Crawler
+	client       *http.Client
+	pollInterval time.Duration
+	trapRules    web.TrapRules
+}
+
+// WorkerOption configures a Worker.
+type WorkerOption func(w *Worker)
+
+// WithWorkerID sets the worker name sent to the Coordinator, "<hostname>-<pid>" is used by default.
+func WithWorkerID(id string) WorkerOption {
+	return func(w *Worker) {
+		w.id = id
+	}
+}
+
+// WithCrawler sets the crawler which fetches and extracts pages.
+func WithCrawler(crawler *web.Crawler) WorkerOption {
+	return func(w *Worker) {
+		w.crawler = crawler
+	}
+}
+
+// WithHTTPClient sets the client of the Coordinator API.
+func WithHTTPClient(client *http.Client) WorkerOption {
+	return func(w *Worker) {
+		w.client = client
+	}
+}
+
+// WithPollInterval sets how long the Worker waits when the Coordinator has no links to hand out.
+func WithPollInterval(interval time.Duration) WorkerOption {
+	return func(w *Worker) {
+		w.pollInterval = interval
+	}
+}
+
+// WithTrapRules sets heuristics of crawler traps, links which trip them aren't reported as found links.
+// web.DefaultTrapRules are used by default, rules depending on other links of the crawl aren't checked.
+func WithTrapRules(rules web.TrapRules) WorkerOption {
+	return func(w *Worker) {
+		w.trapRules = rules
+	}
+}
+
+// NewWorker creates a Worker of the Coordinator served by coordinatorURL.
+func NewWorker(coordinatorURL string, options ...WorkerOption) *Worker {
+	hostname, _ := os.Hostname()
+	w := &Worker{
+		id:           fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		coordinator:  coordinatorURL,
+		crawler:      web.NewCrawler(),
+		client:       &http.Client{Timeout: 30 * time.Second},
+		pollInterval: DefaultPollInterval,
+		trapRules:    web.DefaultTrapRules(),
+	}
+	for _, option := range options {
+		option(w)
+	}
+
+	return w
+}
+
+// Run fetches leased links until the crawl is done or ctx is canceled.
+// Links of a Lease are fetched one by one with Lease.Delay between them, so a host is never fetched concurrently.
+func (w *Worker) Run(ctx context.Context) error {
+	for {
+		l, ok, err := w.lease(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		if len(l.Links) == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(w.pollInterval):
+			}
+			continue
+		}
+
+		report := Report{LeaseID: l.ID, Results: make([]Result, 0, len(l.Links))}
+		for i, link := range l.Links {
+			if i != 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(l.Delay):
+				}
+			}
+			// an unreported lease expires and its links are fetched by another worker.
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			page, links, err := w.crawler.FetchPage(link.URL)
+			r := Result{Link: link, Page: page}
+			r.Links, r.Quarantined = w.quarantine(links)
+			if err != nil {
+				r.Error = err.Error()
+			}
+			report.Results = append(report.Results, r)
+		}
+
+		if err := w.complete(ctx, report); err != nil {
+			return err
+		}
+	}
+}
+
+// quarantine splits links found on a page into the ones to report and the ones which trip trapRules.
+func (w *Worker) quarantine(links []web.Link) ([]web.Link, map[string]string) {
+	reported := make([]web.Link, 0, len(links))
+	quarantined := map[string]string{}
+	for _, link := range links {
+		if rule := w.trapRules.Trap(link.URL); rule != "" {
+			quarantined[link.URL] = rule
+			continue
+		}
+		reported = append(reported, link)
+	}
+
+	return reported, quarantined
+}
+
+// lease requests links from the Coordinator. Empty Lease is returned when there is nothing to fetch at the moment,
+// false is returned when the crawl is done.
+func (w *Worker) lease(ctx context.Context) (Lease, bool, error) {
+	resp, err := w.post(ctx, LeasePath, leaseRequest{Worker: w.id})
+	if err != nil {
+		return Lease{}, false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusGone:
+		return Lease{}, false, nil
+	case http.StatusNoContent:
+		return Lease{}, true, nil
+	case http.StatusOK:
+		var l Lease
+		if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+			return Lease{}, false, fmt.Errorf("cannot decode lease: %w", err)
+		}
+		return l, true, nil
+	}
+
+	return Lease{}, false, fmt.Errorf("cannot lease links, status: %s", resp.Status)
+}
+
+// complete reports the Lease, a lease expired on the Coordinator side is dropped.
+func (w *Worker) complete(ctx context.Context, report Report) error {
+	resp, err := w.post(ctx, CompletePath, report)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusConflict:
+		return nil
+	}
+
+	return fmt.Errorf("cannot complete lease %s, status: %s", report.LeaseID, resp.Status)
+}
+
+func (w *Worker) post(ctx context.Context, path string, body any) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.coordinator+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot call coordinator: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,37 @@
+package web
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// anchorTexts collects texts of links by the link target, every text of a target is kept once.
+type anchorTexts struct {
+	mutex *sync.RWMutex
+	texts map[string][]string
+}
+
+func newAnchorTexts() *anchorTexts {
+	return &anchorTexts{mutex: &sync.RWMutex{}, texts: map[string][]string{}}
+}
+
+func (a *anchorTexts) add(target, text string) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if !slices.Contains(a.texts[target], text) {
+		a.texts[target] = append(a.texts[target], text)
+	}
+}
+
+func (a *anchorTexts) get(target string) []string {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return slices.Clone(a.texts[target])
+}
@@ -0,0 +1,237 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// CassetteMode defines whether a Cassette records, replays or passes requests through.
+type CassetteMode int
+
+const (
+	// CassettePassthrough fetches pages without recording.
+	CassettePassthrough CassetteMode = iota
+	// CassetteRecord fetches pages and saves responses to the cassette directory.
+	CassetteRecord
+	// CassetteReplay returns saved responses without network access.
+	CassetteReplay
+)
+
+var ErrNotRecorded = errors.New("request is not recorded in the cassette")
+
+var cassetteNameRe = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// recordedErrors are errors of a PageFetcher which are matched by errors.Is after replay.
+var recordedErrors = []error{ErrPageDoesNotExist, ErrRetriesExceeded}
+
+// recording is a saved response or a failed fetch, one file per URL.
+type recording struct {
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+	// Encoding is "base64" for bodies which aren't valid UTF-8.
+	Encoding string `json:"encoding,omitempty"`
+	// Error is the message of the fetch error, Wraps is the message of the recordedErrors one it wraps.
+	Error string `json:"error,omitempty"`
+	Wraps string `json:"wraps,omitempty"`
+}
+
+// replayedError is a recorded fetch error, it wraps the same of recordedErrors as the original one.
+type replayedError struct {
+	message string
+	err     error
+}
+
+func (e *replayedError) Error() string {
+	return e.message
+}
+
+func (e *replayedError) Unwrap() error {
+	return e.err
+}
+
+// Cassette is a PageFetcher decorator which records responses and fetch errors to a directory and replays them later,
+// so crawls can be repeated offline with the same results.
+type Cassette struct {
+	fetcher PageFetcher
+	dir     string
+	mode    CassetteMode
+
+	mutex     *sync.Mutex
+	unmatched []string
+}
+
+// NewCassette creates a Cassette of the fetcher stored in dir, the directory is created in CassetteRecord mode.
+// The fetcher is used to parse pages in all modes and to fetch them in CassetteRecord and CassettePassthrough modes.
+func NewCassette(fetcher PageFetcher, dir string, mode CassetteMode) (*Cassette, error) {
+	if mode == CassetteRecord {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("cannot create cassette directory: %w", err)
+		}
+	}
+
+	return &Cassette{fetcher: fetcher, dir: dir, mode: mode, mutex: &sync.Mutex{}}, nil
+}
+
+func (c *Cassette) FilterPageElements(body io.ReadCloser, option FilterOption) []Tag {
+	return c.fetcher.FilterPageElements(body, option)
+}
+
+func (c *Cassette) ParseTree(body io.ReadCloser) (*Tag, error) {
+	return c.fetcher.ParseTree(body)
+}
+
+func (c *Cassette) Get(url string) (*http.Response, error) {
+	switch c.mode {
+	case CassetteRecord:
+		return c.record(url)
+	case CassetteReplay:
+		return c.replay(url)
+	default:
+		return c.fetcher.Get(url)
+	}
+}
+
+func (c *Cassette) ExistPage(url string) bool {
+	resp, err := c.Get(url)
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+
+	return resp.StatusCode != http.StatusNotFound
+}
+
+// Unmatched returns URLs requested in CassetteReplay mode which aren't recorded.
+func (c *Cassette) Unmatched() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return slices.Clone(c.unmatched)
+}
+
+func (c *Cassette) record(url string) (*http.Response, error) {
+	resp, err := c.fetcher.Get(url)
+	if err != nil {
+		r := recording{URL: url, Error: err.Error()}
+		if resp != nil {
+			r.Status, r.Header = resp.StatusCode, resp.Header
+		}
+		for _, recorded := range recordedErrors {
+			if errors.Is(err, recorded) {
+				r.Wraps = recorded.Error()
+				break
+			}
+		}
+		if saveErr := c.save(r); saveErr != nil {
+			return nil, errors.Join(err, saveErr)
+		}
+
+		return resp, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s page: %w", url, err)
+	}
+
+	r := recording{URL: url, Status: resp.StatusCode, Header: resp.Header, Body: string(body)}
+	if !utf8.Valid(body) {
+		r.Body, r.Encoding = base64.StdEncoding.EncodeToString(body), "base64"
+	}
+	if err := c.save(r); err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+func (c *Cassette) save(r recording) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode %s recording: %w", r.URL, err)
+	}
+	if err := os.WriteFile(c.path(r.URL), data, 0o600); err != nil {
+		return fmt.Errorf("cannot save %s recording: %w", r.URL, err)
+	}
+
+	return nil
+}
+
+func (c *Cassette) replay(url string) (*http.Response, error) {
+	data, err := os.ReadFile(c.path(url))
+	if errors.Is(err, os.ErrNotExist) {
+		c.mutex.Lock()
+		c.unmatched = append(c.unmatched, url)
+		c.mutex.Unlock()
+
+		return nil, fmt.Errorf("%w: %s, cassette: %s", ErrNotRecorded, url, c.dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s recording: %w", url, err)
+	}
+
+	var r recording
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("cannot decode %s recording: %w", url, err)
+	}
+	if r.Error != "" {
+		replayed := &replayedError{message: r.Error}
+		for _, recorded := range recordedErrors {
+			if r.Wraps == recorded.Error() {
+				replayed.err = recorded
+			}
+		}
+
+		return nil, replayed
+	}
+
+	body := []byte(r.Body)
+	if r.Encoding == "base64" {
+		if body, err = base64.StdEncoding.DecodeString(r.Body); err != nil {
+			return nil, fmt.Errorf("cannot decode %s recording body: %w", url, err)
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}, nil
+}
+
+// path returns the recording file of the URL, e.g. "go.dev-learn-1a2b3c4d5e6f7a8b.json".
+// The hash keeps names of URLs which differ only in special characters unique.
+func (c *Cassette) path(url string) string {
+	hash := sha256.Sum256([]byte(url))
+	name := strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+	name = strings.Trim(cassetteNameRe.ReplaceAllString(name, "-"), "-")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+
+	return filepath.Join(c.dir, name+"-"+hex.EncodeToString(hash[:8])+".json")
+}
@@ -0,0 +1,142 @@
+package web_test
+
+import (
+	"fmt"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Language", "en")
+			_, _ = w.Write([]byte(`<html><body><p>Index</p><a href="/binary">binary</a><a href="/missing">missing</a></body></html>`))
+		case "/binary":
+			_, _ = w.Write([]byte{'<', 'p', '>', 0xff, 0xfe, '<', '/', 'p', '>'})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	// given
+	recorder, err := web.NewCassette(web.NewClient(web.BaseRetryPolicy(), 0), dir, web.CassetteRecord)
+	require.NoError(t, err)
+	recorded, err := web.NewCrawler(web.WithPageFetcher(recorder)).ScrapePages(server.URL + "/")
+	require.NoError(t, err)
+	server.Close()
+
+	t.Run("should replay recorded responses without network", func(t *testing.T) {
+		// given
+		player, err := web.NewCassette(web.NewClient(web.BaseRetryPolicy(), 0), dir, web.CassetteReplay)
+		require.NoError(t, err)
+
+		// when
+		replayed, err := web.NewCrawler(web.WithPageFetcher(player)).ScrapePages(server.URL + "/")
+
+		// expected
+		require.NoError(t, err)
+		assert.Len(t, replayed, 2)
+		assert.Equal(t, recorded, replayed)
+		assert.Empty(t, player.Unmatched())
+
+		resp, err := player.Get(server.URL + "/")
+		require.NoError(t, err)
+		assert.Equal(t, "en", resp.Header.Get("Content-Language"))
+		assert.False(t, player.ExistPage(server.URL+"/missing"))
+	})
+
+	t.Run("should report unmatched requests", func(t *testing.T) {
+		// given
+		player, err := web.NewCassette(web.NewClient(web.BaseRetryPolicy(), 0), dir, web.CassetteReplay)
+		require.NoError(t, err)
+
+		// when
+		_, err = player.Get(server.URL + "/new")
+
+		// expected
+		require.ErrorIs(t, err, web.ErrNotRecorded)
+		assert.Contains(t, err.Error(), server.URL+"/new")
+		assert.Equal(t, []string{server.URL + "/new"}, player.Unmatched())
+	})
+
+	t.Run("should not record in passthrough mode", func(t *testing.T) {
+		// given
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("<p>page</p>"))
+		}))
+		defer other.Close()
+		passthroughDir := t.TempDir()
+		passthrough, err := web.NewCassette(web.NewClient(web.BaseRetryPolicy(), 0), passthroughDir, web.CassettePassthrough)
+		require.NoError(t, err)
+
+		// when
+		resp, err := passthrough.Get(other.URL)
+
+		// expected
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		files, err := os.ReadDir(passthroughDir)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("should replay failed fetches", func(t *testing.T) {
+		// given
+		site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`<html><body><a href="/missing">missing</a></body></html>`))
+		}))
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+		failedDir := t.TempDir()
+		fetcher := notFoundFetcher{web.NewClient(web.BaseRetryPolicy(), 0)}
+		recorder, err := web.NewCassette(fetcher, failedDir, web.CassetteRecord)
+		require.NoError(t, err)
+		recorded, err := web.NewCrawler(web.WithPageFetcher(recorder)).ScrapePages(site.URL + "/")
+		require.NoError(t, err)
+		_, downErr := recorder.Get(down.URL + "/")
+		require.Error(t, downErr)
+		site.Close()
+		player, err := web.NewCassette(fetcher, failedDir, web.CassetteReplay)
+		require.NoError(t, err)
+
+		// when
+		replayed, err := web.NewCrawler(web.WithPageFetcher(player)).ScrapePages(site.URL + "/")
+		_, missingErr := player.Get(site.URL + "/missing")
+		_, replayedDownErr := player.Get(down.URL + "/")
+
+		// expected
+		require.NoError(t, err)
+		assert.Equal(t, recorded, replayed)
+		assert.ErrorIs(t, missingErr, web.ErrPageDoesNotExist)
+		assert.EqualError(t, replayedDownErr, downErr.Error())
+		assert.NotErrorIs(t, replayedDownErr, web.ErrNotRecorded)
+		assert.Empty(t, player.Unmatched())
+	})
+}
+
+// notFoundFetcher returns ErrPageDoesNotExist for pages answered with 404 Not Found.
+type notFoundFetcher struct {
+	web.PageFetcher
+}
+
+func (f notFoundFetcher) Get(url string) (*http.Response, error) {
+	resp, err := f.PageFetcher.Get(url)
+	if err == nil && resp.StatusCode == http.StatusNotFound {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", web.ErrPageDoesNotExist, url)
+	}
+
+	return resp, err
+}
@@ -22,6 +22,15 @@ type Page struct {
 	Type    string
 	URL     string
 	Content []string
+	Title   string
+	// Fields are named page parts, e.g. FieldTitle, FieldHeadings or fields of a Profile.
+	Fields map[string][]string
+	// Relevance is the relevance of the page to the Focus topic, it's zero when the crawl isn't focused.
+	Relevance float64
+	// Language is the ISO 639-1 code of the page language, empty when it's unknown.
+	Language string
+	// Metadata is structured data of the page, e.g. its author and publish date.
+	Metadata Metadata
 }
 
 // Client provides API to collect Web data.
@@ -47,6 +56,8 @@ const (
 	Body
 	// CloseTag is <a/> kind of Tag.
 	CloseTag
+	// Document is the root Tag of a tree built by ParseTree.
+	Document
 )
 
 // String returns a string representation of the TagType.
@@ -62,19 +73,13 @@ func (t TagType) String() string {
 		return "CloseTag"
 	case SelfCloseTag:
 		return "SelfCloseTag"
+	case Document:
+		return "Document"
 	}
 
 	return "Invalid(" + strconv.Itoa(int(t)) + ")"
 }
 
-// TODO: maybe Tag type should be refactored to
-// type Tag struct {
-//	Open, Close       string
-//	Body string
-//  Attributes map[string]string
-//  InnerTags []Tag
-//}
-
 // Tag contains information about Tag(usually HTML one).
 type Tag struct {
 	// Name is a value inside <> braces.
@@ -84,6 +89,10 @@ type Tag struct {
 	Type       TagType
 	Raw        []byte
 	Attributes map[string]string
+
+	// Parent and Children are set only for tags built by ParseTree.
+	Parent   *Tag
+	Children []*Tag
 }
 
 // provideType converts html.TokenType to internal TagType.
@@ -298,11 +307,10 @@ func (c *Client) Get(url string) (*http.Response, error) {
 
 func (c *Client) ExistPage(url string) bool {
 	resp, err := c.Get(url)
-
 	if err != nil {
-		fmt.Println(err)
 		return false
 	}
+	_ = resp.Body.Close()
 
 	return !(resp.StatusCode == http.StatusNotFound)
 }
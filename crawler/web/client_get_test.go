@@ -4,46 +4,40 @@ import (
 	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
 func TestClient_GetSuccess(t *testing.T) {
-	// check that it redirects automatically
+	// check that it retries too many requests
 
 	c := web.NewClient(web.BaseRetryPolicy(), 5)
 
-	go func() {
-		n := 4
-		helloHandler := func(w http.ResponseWriter, req *http.Request) {
-			n--
-			if n == 0 {
-				w.WriteHeader(http.StatusOK)
-			}
-			w.WriteHeader(http.StatusTooManyRequests)
+	n := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n--
+		if n == 0 {
+			w.WriteHeader(http.StatusOK)
+			return
 		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
 
-		http.HandleFunc("/", helloHandler)
-
-		assert.NoError(t, http.ListenAndServe(":8081", nil))
-	}()
-	_, err := c.Get("http://localhost:8081/")
+	_, err := c.Get(server.URL + "/")
 	assert.NoError(t, err)
 }
 
 func TestClient_GetWithError(t *testing.T) {
-	// check that it redirects automatically
+	// check that it stops retrying server errors
 
 	c := web.NewClient(web.BaseRetryPolicy(), 5)
 
-	go func() {
-		helloHandler := func(w http.ResponseWriter, req *http.Request) {
-			w.WriteHeader(http.StatusInternalServerError)
-		}
-
-		http.HandleFunc("/error", helloHandler)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
 
-		assert.NoError(t, http.ListenAndServe(":8080", nil))
-	}()
-	_, err := c.Get("http://localhost:8080/error")
-	assert.EqualError(t, err, "cannot fetch http://localhost:8080/error page: exceeded retries: last status code 500")
+	_, err := c.Get(server.URL + "/error")
+	assert.EqualError(t, err, "cannot fetch "+server.URL+"/error page: exceeded retries: last status code 500")
 }
@@ -0,0 +1,67 @@
+package web_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestClient_ParseTree(t *testing.T) {
+	c := web.NewClient(web.BaseRetryPolicy(), 5)
+
+	page := `<!DOCTYPE html>
+			<html lang="en">
+			<head>
+				<meta charset="UTF-8">
+				<title>Two Links Example</title>
+			</head>
+			<body>
+				<!-- navigation -->
+				<ul>
+					<li><a href="https://www.example.com">Link 1</a>
+					<li><a href="https://www.example.org">Link 2</a>
+				</ul>
+				<p>First<br>line<p>Second line
+			</body>
+			</html>`
+
+	// when
+	root, err := c.ParseTree(io.NopCloser(strings.NewReader(page)))
+
+	// expected
+	require.NoError(t, err)
+	assert.Equal(t, web.Document, root.Type)
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, web.Doctype, root.Children[0].Type)
+
+	html := root.Children[1]
+	assert.Equal(t, "html", html.Name)
+	assert.Equal(t, "en", html.Attributes["lang"])
+	assert.Same(t, root, html.Parent)
+	require.Len(t, html.Children, 2)
+
+	head, body := html.Children[0], html.Children[1]
+	assert.Equal(t, "head", head.Name)
+	assert.Equal(t, "body", body.Name)
+	assert.Equal(t, []string{"meta", "title"}, names(head.Children))
+	assert.Equal(t, "Two Links Example", head.Children[1].Text())
+
+	require.Equal(t, []string{"ul", "p", "p"}, names(body.Children))
+	ul := body.Children[0]
+	assert.Equal(t, []string{"li", "li"}, names(ul.Children))
+	assert.Equal(t, "https://www.example.org", ul.Children[1].Children[0].Attributes["href"])
+	assert.Equal(t, "First line", body.Children[1].Text())
+	assert.Equal(t, "Second line", body.Children[2].Text())
+}
+
+func names(tags []*web.Tag) []string {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, tag.Name)
+	}
+
+	return result
+}
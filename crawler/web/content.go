@@ -0,0 +1,194 @@
+package web
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExtractionMode defines which text of a page is passed to the lexer.
+type ExtractionMode int
+
+const (
+	// ExtractionFull keeps all page text except tags from DefaultContentFilterOption.
+	ExtractionFull ExtractionMode = iota
+	// ExtractionMainContent keeps only the article body found by MainContent.
+	ExtractionMainContent
+)
+
+var (
+	unlikelyCandidateRe = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|consent|disqus|` +
+		`extra|foot|header|legends|menu|modal|nav|popup|promo|related|remark|rss|share|shoutbox|sidebar|` +
+		`skyscraper|social|sponsor|subscribe|tags|toolbar|widget|ad-break|agegate|pager`)
+
+	maybeCandidateRe = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+
+	positiveCandidateRe = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|` +
+		`post|text|blog|story`)
+
+	negativeCandidateRe = regexp.MustCompile(`(?i)hidden|^hid$|hid$|hid |^hid |banner|combx|comment|com-|` +
+		`contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|` +
+		`sidebar|skyscraper|sponsor|shopping|tags|tool|widget|cookie|nav|menu`)
+)
+
+// boilerplateTags never contain the main content of a page.
+var boilerplateTags = []string{
+	"script", "style", "noscript", "iframe", "nav", "footer", "aside", "form",
+	"button", "select", "svg", "canvas", "template", "head",
+}
+
+// paragraphTags are scored by MainContent, their score is propagated to the parent and grandparent.
+var paragraphTags = []string{"p", "pre", "td", "blockquote", "li", "dd"}
+
+const (
+	// minParagraphLength is the minimal paragraph length to take it into account.
+	minParagraphLength = 25
+	// maxLinkDensity is a part of link text in a block after which the block is treated as navigation.
+	maxLinkDensity = 0.5
+	classWeight    = 25
+)
+
+// MainContent returns the Tag which most likely contains the main content(article body) of a page.
+//
+// Candidates are scored readability-style: paragraphs with a lot of text and commas add score
+// to their parent and grandparent, class and id names like "content" or "sidebar" add or remove score,
+// and a final score is reduced by a link density of the candidate.
+// When no candidate is found, root is returned.
+func MainContent(root *Tag) *Tag {
+	scores := map[*Tag]float64{}
+	candidates := make([]*Tag, 0)
+
+	addScore := func(tag *Tag, score float64) {
+		if tag == nil || !tag.IsElement() {
+			return
+		}
+		if _, ok := scores[tag]; !ok {
+			scores[tag] = initialScore(tag)
+			candidates = append(candidates, tag)
+		}
+		scores[tag] += score
+	}
+
+	root.Walk(func(tag *Tag) bool {
+		if tag.Type != Body && isBoilerplate(tag) {
+			return false
+		}
+		if !slices.Contains(paragraphTags, tag.Name) {
+			return true
+		}
+
+		text := tag.Text()
+		length := utf8.RuneCountInString(text)
+		if length < minParagraphLength {
+			return true
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(length)/100, 3)
+		addScore(tag.Parent, score)
+		if tag.Parent != nil {
+			addScore(tag.Parent.Parent, score/2)
+		}
+
+		return true
+	})
+
+	var best *Tag
+	bestScore := 0.0
+	for _, candidate := range candidates {
+		score := scores[candidate] * (1 - linkDensity(candidate))
+		if best == nil || score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	if best == nil {
+		return root
+	}
+
+	return best
+}
+
+// ContentText returns text blocks inside the Tag skipping boilerplate and link-heavy blocks.
+func ContentText(tag *Tag) []string {
+	return collectText(tag, isNoise)
+}
+
+// isNoise reports whether the Tag is boilerplate or a link-heavy block inside the main content.
+func isNoise(tag *Tag) bool {
+	if isBoilerplate(tag) {
+		return true
+	}
+
+	if slices.Contains(paragraphTags, tag.Name) || tag.Name == "ul" || tag.Name == "ol" || tag.Name == "div" {
+		return linkDensity(tag) > maxLinkDensity
+	}
+
+	return false
+}
+
+// isBoilerplate reports whether the Tag is navigation, footer, comments or any other page chrome.
+func isBoilerplate(tag *Tag) bool {
+	if slices.Contains(boilerplateTags, tag.Name) {
+		return true
+	}
+	if tag.Name == "body" || tag.Name == "html" || tag.Name == "article" || tag.Name == "main" {
+		return false
+	}
+	if tag.Attributes["role"] == "navigation" || tag.Attributes["aria-hidden"] == "true" {
+		return true
+	}
+
+	match := tag.Attributes["class"] + " " + tag.ID()
+	return unlikelyCandidateRe.MatchString(match) && !maybeCandidateRe.MatchString(match)
+}
+
+// initialScore scores a candidate by its name, class and id.
+func initialScore(tag *Tag) float64 {
+	score := 0.0
+	switch tag.Name {
+	case "article", "main":
+		score += 10
+	case "div":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+
+	for _, attr := range []string{tag.Attributes["class"], tag.ID()} {
+		if attr == "" {
+			continue
+		}
+		if negativeCandidateRe.MatchString(attr) {
+			score -= classWeight
+		}
+		if positiveCandidateRe.MatchString(attr) {
+			score += classWeight
+		}
+	}
+
+	return score
+}
+
+// linkDensity returns a part of the Tag's text placed inside links.
+func linkDensity(tag *Tag) float64 {
+	textLength := utf8.RuneCountInString(tag.Text())
+	if textLength == 0 {
+		return 0
+	}
+
+	linkLength := 0
+	tag.Walk(func(t *Tag) bool {
+		if t.Name == htmlLinkTag && t.IsElement() {
+			linkLength += utf8.RuneCountInString(t.Text())
+			return false
+		}
+		return true
+	})
+
+	return float64(linkLength) / float64(textLength)
+}
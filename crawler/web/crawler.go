@@ -5,8 +5,9 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
-	gorecslices "github.com/mishaprokop4ik/gorecs-search/pkg/slices"
 	"io"
+	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -90,167 +91,564 @@ var (
 
 type PageFetcher interface {
 	FilterPageElements(body io.ReadCloser, option FilterOption) []Tag
+	ParseTree(body io.ReadCloser) (*Tag, error)
 	Get(url string) (*http.Response, error)
 	ExistPage(url string) bool
 }
 
 const htmlLinkTag = "a"
 
+var linkSelector = MustCompileSelector("a[href]")
+
+const (
+	DefaultWorkers = 10
+	// DefaultMaxDepth crawls the base page and pages it links to.
+	DefaultMaxDepth = 1
+)
+
 type Crawler struct {
 	client PageFetcher
 	sites  map[string][]string
 	mutex  *sync.RWMutex
+
+	extractionMode ExtractionMode
+	profiles       *Profiles
+	observer       Observer
+	newFrontier    func() Frontier
+	focus          *Focus
+	trapRules      TrapRules
+	// last is the state of the last started crawl, it's guarded by mutex.
+	last *crawlState
+
+	workers  int
+	maxDepth int
+	maxPages int
+}
+
+// CrawlerOption configures a Crawler.
+type CrawlerOption func(c *Crawler)
+
+// WithExtractionMode sets which text of crawled pages is returned.
+func WithExtractionMode(mode ExtractionMode) CrawlerOption {
+	return func(c *Crawler) {
+		c.extractionMode = mode
+	}
 }
 
-func NewCrawler() *Crawler {
-	return &Crawler{client: NewClient(BaseRetryPolicy(), 5), mutex: &sync.RWMutex{}}
+// WithProfiles sets per-site extraction profiles, which are matched by the URL of each crawled page.
+func WithProfiles(profiles *Profiles) CrawlerOption {
+	return func(c *Crawler) {
+		c.profiles = profiles
+	}
 }
 
-func (s *Crawler) Scrape(baseURL string) (map[string][]string, error) {
-	result := make(map[string][]string)
+// WithObserver sets the Observer notified about the crawl progress.
+// By default only links quarantined by TrapRules are logged with slog.Default.
+func WithObserver(observer Observer) CrawlerOption {
+	return func(c *Crawler) {
+		c.observer = observer
+	}
+}
+
+// WithFrontier sets a function creating the Frontier of every crawl, the Frontier decides which page is fetched next.
+// BFSFrontier is used by default.
+func WithFrontier(newFrontier func() Frontier) CrawlerOption {
+	return func(c *Crawler) {
+		c.newFrontier = newFrontier
+	}
+}
+
+// WithWorkers sets a number of pages fetched concurrently.
+func WithWorkers(workers int) CrawlerOption {
+	return func(c *Crawler) {
+		c.workers = max(workers, 1)
+	}
+}
+
+// WithMaxDepth sets a number of links from the base page after which links aren't followed.
+func WithMaxDepth(depth int) CrawlerOption {
+	return func(c *Crawler) {
+		c.maxDepth = depth
+	}
+}
+
+// WithMaxPages sets a page budget of a crawl, zero means no limit.
+func WithMaxPages(pages int) CrawlerOption {
+	return func(c *Crawler) {
+		c.maxPages = pages
+	}
+}
 
-	if !s.client.ExistPage(baseURL) {
-		return map[string][]string{}, fmt.Errorf("%s, url: %s", ErrPageDoesNotExist, baseURL)
+// WithTrapRules sets heuristics of crawler traps, DefaultTrapRules are used by default.
+func WithTrapRules(rules TrapRules) CrawlerOption {
+	return func(c *Crawler) {
+		c.trapRules = rules
 	}
+}
 
-	basePageContent, err := s.pullContent(baseURL)
+// WithPageFetcher replaces the default Client.
+func WithPageFetcher(client PageFetcher) CrawlerOption {
+	return func(c *Crawler) {
+		c.client = client
+	}
+}
 
-	links, err := s.pullReferences(baseURL)
+func NewCrawler(options ...CrawlerOption) *Crawler {
+	c := &Crawler{
+		client:   NewClient(BaseRetryPolicy(), 5),
+		mutex:    &sync.RWMutex{},
+		observer: trapObserver{logger: slog.Default()},
+		workers:  DefaultWorkers,
+		maxDepth: DefaultMaxDepth,
+
+		trapRules: DefaultTrapRules(),
+		last:      newCrawlState(NewBFSFrontier(), nil, TrapRules{}),
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// Scrape returns the content of the page by baseURL and pages it links to.
+func (s *Crawler) Scrape(baseURL string) (map[string][]string, error) {
+	pages, err := s.ScrapePages(baseURL)
 	if err != nil {
-		return map[string][]string{}, fmt.Errorf("failed to pull references by %s link, err: %w",
-			baseURL, err)
+		return map[string][]string{}, err
 	}
 
+	result := make(map[string][]string, len(pages))
+	for link, page := range pages {
+		result[link] = page.Content
+	}
+
+	return result, nil
+}
+
+// ScrapePages is like Scrape but returns whole extracted pages.
+//
+// Pages are crawled by workers in the order defined by the Frontier, starting from baseURL and
+// following links until the maximal depth or the page budget is reached.
+func (s *Crawler) ScrapePages(baseURL string) (map[string]Page, error) {
+	result := make(map[string]Page)
+	crawl := s.newCrawl()
+	err := s.crawl(context.Background(), crawl, baseURL, func(r visitResult) bool {
+		if r.err == nil {
+			result[r.link.URL] = r.page
+		}
+		return true
+	})
 	if err != nil {
-		return map[string][]string{}, fmt.Errorf("failed to pull content from %s url, err: %w",
-			baseURL, err)
+		return map[string]Page{}, err
 	}
 
-	result[baseURL] = basePageContent
+	// anchor texts are attached after the crawl, so texts of links found after the target page are kept too.
+	for link, page := range result {
+		if anchors := crawl.anchors.get(link); len(anchors) != 0 {
+			page.Fields[FieldAnchors] = anchors
+			result[link] = page
+		}
+	}
 
-	pagech := make(chan Page)
-	defer func() { close(pagech) }()
+	return result, nil
+}
 
-	errch := make(chan error)
-	defer func() { close(errch) }()
+// CrawlResult is a page of a streamed crawl, Err is set when the page can't be fetched.
+type CrawlResult struct {
+	Page Page
+	Err  error
+	// AnchorTexts are texts of links to an already sent page found after it was sent,
+	// such a result is sent when the crawl is finished and has only the page URL.
+	AnchorTexts []string
+}
 
-	stopch := make(chan struct{})
-	defer func() { close(stopch) }()
-	for i, link := range links {
-		go func(link string, last bool) {
-			defer func() {
-				if r := recover(); r != nil {
-					fmt.Println("Recovered in getting content", "url", link, "error", r)
-				}
-			}()
+// Stream is like ScrapePages but sends pages to the returned channel as soon as they are fetched.
+//
+// The channel isn't buffered, so the crawl waits for a slow consumer: no more than the number of workers
+// pages are fetched ahead of it. Pages have anchor texts of links found before the page is sent,
+// texts of links found later are sent in results with AnchorTexts when the crawl is finished.
+// A failed page is sent with Err and only its URL. The channel is closed when the crawl is finished or ctx is canceled,
+// the last result has Err set when the base page can't be fetched.
+func (s *Crawler) Stream(ctx context.Context, baseURL string) <-chan CrawlResult {
+	results := make(chan CrawlResult)
+	send := func(r CrawlResult) bool {
+		select {
+		case results <- r:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(results)
 
-			if !s.client.ExistPage(link) {
-				errch <- fmt.Errorf("%s: %w", link, ErrPageDoesNotExist)
-				return
+		type sentPage struct {
+			url     string
+			anchors int
+		}
+		crawl := s.newCrawl()
+		sent := make([]sentPage, 0)
+		err := s.crawl(ctx, crawl, baseURL, func(r visitResult) bool {
+			if r.err != nil {
+				return send(CrawlResult{Page: Page{URL: r.link.URL}, Err: r.err})
 			}
-			content, err := s.pullContent(link)
-			if err != nil {
-				errch <- err
-				return
+			anchors := crawl.anchors.get(r.link.URL)
+			if len(anchors) != 0 {
+				r.page.Fields[FieldAnchors] = anchors
 			}
-
-			page := Page{
-				URL:     link,
-				Content: content,
+			sent = append(sent, sentPage{url: r.link.URL, anchors: len(anchors)})
+			return send(CrawlResult{Page: r.page})
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				send(CrawlResult{Page: Page{URL: baseURL}, Err: err})
 			}
-			pagech <- page
+			return
+		}
 
-			if last {
-				stopch <- struct{}{}
-				return
+		// anchor texts are only added to a crawl, so texts after the sent ones are found later.
+		for _, page := range sent {
+			if anchors := crawl.anchors.get(page.url); len(anchors) > page.anchors {
+				if !send(CrawlResult{Page: Page{URL: page.url}, AnchorTexts: anchors[page.anchors:]}) {
+					return
+				}
 			}
-		}(link, i == len(links)-1)
+		}
+	}()
+
+	return results
+}
+
+// crawlState is the state of a single crawl, so crawls of one Crawler, even concurrent ones, don't share it.
+// visited, traps, frontier and focus are used only by the crawl loop, focus is nil when the crawl isn't focused.
+type crawlState struct {
+	anchors    *anchorTexts
+	graph      *LinkGraph
+	frontier   Frontier
+	focus      *focuser
+	visited    map[string]bool
+	traps      *trapDetector
+	mutex      *sync.RWMutex
+	quarantine map[string]string
+}
+
+func newCrawlState(frontier Frontier, focus *focuser, rules TrapRules) *crawlState {
+	return &crawlState{
+		anchors:    newAnchorTexts(),
+		graph:      NewLinkGraph(),
+		frontier:   frontier,
+		focus:      focus,
+		visited:    map[string]bool{},
+		traps:      newTrapDetector(rules),
+		mutex:      &sync.RWMutex{},
+		quarantine: map[string]string{},
+	}
+}
+
+// newCrawl creates the state of a new crawl, AnchorTexts, LinkGraph and Quarantine return it from now on.
+// A focused crawl uses a PriorityFrontier ordered by its focuser unless a Frontier is set by WithFrontier.
+func (s *Crawler) newCrawl() *crawlState {
+	var focus *focuser
+	if s.focus != nil {
+		focus = newFocuser(*s.focus)
+	}
+	var frontier Frontier
+	switch {
+	case s.newFrontier != nil:
+		frontier = s.newFrontier()
+	case focus != nil:
+		frontier = NewPriorityFrontier(focus.score)
+	default:
+		frontier = NewBFSFrontier()
 	}
+	crawl := newCrawlState(frontier, focus, s.trapRules)
+
+	s.mutex.Lock()
+	s.last = crawl
+	s.mutex.Unlock()
+
+	return crawl
+}
+
+// lastCrawl returns the state of the last started crawl.
+func (s *Crawler) lastCrawl() *crawlState {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.last
+}
 
-Loop:
+// crawl fetches pages starting from baseURL and passes every fetched page and every failed page to emit,
+// except the failed base page: the crawl is aborted with an error instead. The crawl is stopped when emit returns false or ctx is canceled, pages being fetched at the moment are dropped.
+func (s *Crawler) crawl(ctx context.Context, crawl *crawlState, baseURL string, emit func(r visitResult) bool) error {
+	pages := 0
+	start := time.Now()
+	defer func() { s.observer.OnDone(baseURL, pages, time.Since(start)) }()
+
+	crawl.visited[baseURL] = true
+	// the base page is always fetched, it's checked only to be counted in the host budget.
+	crawl.traps.check(baseURL)
+	crawl.frontier.Push(Link{URL: baseURL})
+	s.observer.OnQueued(baseURL, 0)
+
+	resultch := make(chan visitResult)
+	inflight, fetched := 0, 0
+	stopped := false
 	for {
-		select {
-		case page := <-pagech:
-			s.mutex.Lock()
-			result[page.URL] = page.Content
-			s.mutex.Unlock()
-		case err := <-errch:
+		stopped = stopped || ctx.Err() != nil
+		for !stopped && inflight < s.workers && crawl.frontier.Len() != 0 && (s.maxPages == 0 || fetched < s.maxPages) {
+			link, _ := crawl.frontier.Pop()
+			inflight++
+			fetched++
+			go func() {
+				r := s.visitLink(link)
+				crawl.addReferences(r.references)
+				resultch <- r
+			}()
+		}
+		if inflight == 0 {
+			break
+		}
+
+		r := <-resultch
+		inflight--
+		if stopped {
+			continue
+		}
+		if r.err != nil {
+			if r.link.Depth == 0 {
+				s.drainFrontier(crawl, SkipReasonBaseFailed)
+				return fmt.Errorf("failed to pull content from %s url, err: %w", baseURL, r.err)
+			}
 			// TODO: save this link and try to make more attempts
-			fmt.Println("caught an error:", err)
-		case <-stopch:
-			break Loop
+			stopped = !emit(r)
+			continue
 		}
+
+		if crawl.focus != nil {
+			r.page.Relevance = crawl.focus.add(r.page)
+		}
+		pages++
+		s.pushLinks(crawl, r)
+		stopped = !emit(r)
 	}
 
-	return result, nil
+	if stopped {
+		s.drainFrontier(crawl, SkipReasonCanceled)
+		return fmt.Errorf("crawl of %s is stopped: %w", baseURL, context.Cause(ctx))
+	}
+	s.drainFrontier(crawl, SkipReasonPageBudget)
+
+	return nil
 }
 
-func (s *Crawler) pullContent(url string) ([]string, error) {
-	if !s.client.ExistPage(url) {
-		return []string{}, fmt.Errorf("%s, url: %s", ErrPageDoesNotExist, url)
+// pushLinks adds links found on the visited page to the Frontier.
+func (s *Crawler) pushLinks(crawl *crawlState, r visitResult) {
+	if r.link.Depth >= s.maxDepth {
+		return
 	}
 
-	resp, err := s.client.Get(url)
+	for _, link := range r.links {
+		if crawl.visited[link.URL] {
+			continue
+		}
+		crawl.visited[link.URL] = true
+		link.Depth = r.link.Depth + 1
+		if rule := crawl.traps.check(link.URL); rule != "" {
+			s.quarantineLink(crawl, link.URL, rule)
+			continue
+		}
+		if crawl.focus != nil && crawl.focus.prune(link) {
+			s.observer.OnSkipped(link.URL, SkipReasonIrrelevant)
+			continue
+		}
+		crawl.frontier.Push(link)
+		s.observer.OnQueued(link.URL, link.Depth)
+	}
+}
+
+type visitResult struct {
+	link Link
+	page Page
+	// references are all links of the page, links are the unique ones.
+	references []Link
+	links      []Link
+	err        error
+}
+
+func (s *Crawler) visitLink(link Link) (r visitResult) {
+	r.link = link
 	defer func() {
-		if resp != nil && resp.Body != nil {
-			_ = resp.Body.Close()
+		if rec := recover(); rec != nil {
+			r.err = fmt.Errorf("recovered in getting content: %v", rec)
+			s.observer.OnError(link.URL, r.err)
 		}
 	}()
-	if err != nil {
-		return nil, fmt.Errorf("cannot fetch page %s - %w", url, err)
+
+	r.page, r.references, r.err = s.visit(link.URL)
+	r.links = uniqueLinks(r.references)
+
+	return r
+}
+
+// FetchPage fetches and extracts a single page without following its links.
+// Links found on the page are returned with Source set to the page URL, they aren't added to AnchorTexts
+// and LinkGraph, so a long-lived Crawler may fetch any number of pages.
+func (s *Crawler) FetchPage(link string) (Page, []Link, error) {
+	r := s.visitLink(Link{URL: link})
+
+	return r.page, r.links, r.err
+}
+
+// drainFrontier removes links left in the Frontier after the crawl.
+func (s *Crawler) drainFrontier(crawl *crawlState, reason string) {
+	for link, ok := crawl.frontier.Pop(); ok; link, ok = crawl.frontier.Pop() {
+		s.observer.OnSkipped(link.URL, reason)
 	}
-	tags := s.client.FilterPageElements(resp.Body, DefaultContentFilterOption())
+}
 
-	result := make([]string, len(tags))
-	for i, tag := range tags {
-		if tag.Type == Body && strings.ReplaceAll(tag.Body, " ", "") != "" {
-			result[i] = tag.Body
-		}
+// quarantineLink stores the link which tripped the trap rule and reports it as skipped.
+func (s *Crawler) quarantineLink(crawl *crawlState, link, rule string) {
+	crawl.mutex.Lock()
+	crawl.quarantine[link] = rule
+	crawl.mutex.Unlock()
+
+	s.observer.OnSkipped(link, SkipReasonTrap+rule)
+}
+
+// Quarantine returns links of the last started crawl which weren't fetched because they look like crawler traps,
+// by the tripped rule.
+func (s *Crawler) Quarantine() map[string]string {
+	crawl := s.lastCrawl()
+	crawl.mutex.RLock()
+	defer crawl.mutex.RUnlock()
+
+	return maps.Clone(crawl.quarantine)
+}
+
+// LinkGraph returns links between pages found by the last started crawl.
+func (s *Crawler) LinkGraph() *LinkGraph {
+	return s.lastCrawl().graph
+}
+
+// AnchorTexts returns texts of links to the target URL found by the last started crawl, every text once.
+func (s *Crawler) AnchorTexts(target string) []string {
+	return s.lastCrawl().anchors.get(target)
+}
+
+// visit fetches the page by link and extracts its content and references.
+// Errors are reported to the Observer.
+func (s *Crawler) visit(link string) (Page, []Link, error) {
+	page, links, err := s.fetch(link)
+	if err != nil {
+		s.observer.OnError(link, err)
+		return Page{}, nil, err
 	}
 
-	return result, nil
+	return page, links, nil
 }
 
-func (s *Crawler) pullReferences(baseURL string) ([]string, error) {
-	if _, err := url.Parse(baseURL); err != nil {
-		return []string{}, fmt.Errorf("incorrent url param: %w", err)
+func (s *Crawler) fetch(link string) (Page, []Link, error) {
+	base, err := url.Parse(link)
+	if err != nil {
+		return Page{}, nil, fmt.Errorf("incorrent url param: %w", err)
 	}
 
-	resp, err := s.client.Get(baseURL)
+	s.observer.OnFetchStart(link)
+	start := time.Now()
+	resp, err := s.client.Get(link)
 	if err != nil {
-		return []string{}, fmt.Errorf("cannot fetch page: %s, err: %s", baseURL, err)
+		return Page{}, nil, fmt.Errorf("cannot fetch page %s - %w", link, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
-	linkTags := s.client.FilterPageElements(resp.Body, FilterOption{
-		Tags: []string{htmlLinkTag},
-		Type: FilterInclude,
-	})
-	pageLinks := make([]string, 0)
-	for _, tag := range linkTags {
-		if _, ok := tag.Attributes["href"]; ok {
-			link, _, _ := strings.Cut(tag.Attributes["href"], "#")
-			if link == "" {
-				continue
-			}
-			if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
-				if !gorecslices.Exist(link, pageLinks) {
-					pageLinks = append(pageLinks, link)
-				}
-			} else if strings.HasPrefix(link, "//") {
-				link := strings.TrimLeft(link, "/")
-				if !gorecslices.Exist(link, pageLinks) {
-					pageLinks = append(pageLinks, fmt.Sprintf("https://%s", link))
-				}
-			} else if strings.HasPrefix(link, "/") {
-				testURL, _ := url.Parse(baseURL)
 
-				link := fmt.Sprintf("%s://%s/%s", testURL.Scheme, testURL.Host, strings.TrimLeft(link, "/"))
-				if !gorecslices.Exist(link, pageLinks) {
-					pageLinks = append(pageLinks, link)
-				}
-			}
+	body := &countingReader{reader: resp.Body}
+	root, err := s.client.ParseTree(io.NopCloser(body))
+	s.observer.OnFetched(link, resp.StatusCode, body.n, time.Since(start))
+	if err != nil {
+		return Page{}, nil, fmt.Errorf("cannot parse page %s - %w", link, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return Page{}, nil, fmt.Errorf("%w, url: %s", ErrPageDoesNotExist, link)
+	}
+
+	page := Page{URL: link}
+	profile := s.profiles.match(link)
+	extractPage(root, profile, s.extractionMode, &page)
+	page.Language = detectLanguage(page, root, resp.Header)
+	page.Metadata = extractMetadata(root)
+
+	return page, s.pullReferences(base, root, profile), nil
+}
+
+// pullReferences returns all links from the page tree, a link is repeated for every tag referring to it.
+func (s *Crawler) pullReferences(base *url.URL, root *Tag, profile *compiledProfile) []Link {
+	references := make([]Link, 0)
+	for _, tag := range root.Select(linkSelector) {
+		link := resolveReference(base, tag.Attributes["href"])
+		if link == "" {
+			continue
+		}
+		if profile != nil && profile.ignoreLink(link) {
+			s.observer.OnSkipped(link, SkipReasonIgnoredByProfile)
+			continue
 		}
+
+		references = append(references, Link{URL: link, Source: base.String(), AnchorText: tag.Text()})
+	}
+
+	return references
+}
+
+// addReferences stores anchor texts and edges of links found during the crawl.
+func (c *crawlState) addReferences(references []Link) {
+	for _, reference := range references {
+		c.anchors.add(reference.URL, reference.AnchorText)
+		c.graph.AddLink(reference.Source, reference.URL)
+	}
+}
+
+// uniqueLinks returns the first of references to every URL.
+func uniqueLinks(references []Link) []Link {
+	links := make([]Link, 0, len(references))
+	seen := map[string]bool{}
+	for _, reference := range references {
+		if !seen[reference.URL] {
+			seen[reference.URL] = true
+			links = append(links, reference)
+		}
+	}
+
+	return links
+}
+
+// countingReader counts bytes read from the reader.
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+
+	return n, err
+}
+
+// resolveReference returns an absolute link by href of a page by base URL.
+// Empty string is returned for links which can't be crawled, e.g. fragments or relative paths.
+func resolveReference(base *url.URL, href string) string {
+	link, _, _ := strings.Cut(href, "#")
+	switch {
+	case strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://"):
+		return link
+	case strings.HasPrefix(link, "//"):
+		return fmt.Sprintf("https://%s", strings.TrimLeft(link, "/"))
+	case strings.HasPrefix(link, "/"):
+		return fmt.Sprintf("%s://%s/%s", base.Scheme, base.Host, strings.TrimLeft(link, "/"))
 	}
 
-	return pageLinks, nil
+	return ""
 }
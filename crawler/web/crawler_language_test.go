@@ -0,0 +1,76 @@
+package web_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCrawler_FetchPageLanguage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		body     string
+		expected string
+	}{
+		{
+			name:     "should detect the language by the page text",
+			header:   "en",
+			body:     `<html lang="en"><body><p>Пошукова система обходить сторінки та будує індекс слів.</p></body></html>`,
+			expected: "uk",
+		},
+		{
+			name:     "should use the html lang attribute for short texts",
+			header:   "en",
+			body:     `<html lang="de-AT"><body><p>Go 1.22</p></body></html>`,
+			expected: "de",
+		},
+		{
+			name:     "should use the html lang attribute for texts of unknown scripts",
+			header:   "en",
+			body:     `<html lang="zh-CN"><body><p>搜索引擎抓取网页并建立单词索引，用户可以快速找到需要的文档。</p></body></html>`,
+			expected: "zh",
+		},
+		{
+			name:   "should use the html lang attribute for texts of unknown languages",
+			header: "en",
+			body: `<html lang="it"><body><p>Il motore di ricerca visita le pagine del sito, estrae il loro testo e costruisce un indice delle parole. ` +
+				`Quando un utente scrive una richiesta, il motore cerca le parole nell'indice e ordina i documenti secondo la loro pertinenza.</p></body></html>`,
+			expected: "it",
+		},
+		{
+			name:     "should use the Content-Language header without the html lang attribute",
+			header:   "fr-CA, en",
+			body:     `<html><body><p>Go 1.22</p></body></html>`,
+			expected: "fr",
+		},
+		{
+			name:     "should leave the language unknown",
+			body:     `<html><body><p>Go 1.22</p></body></html>`,
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.header != "" {
+					w.Header().Set("Content-Language", tc.header)
+				}
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			// when
+			page, _, err := web.NewCrawler().FetchPage(server.URL)
+
+			// expected
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, page.Language)
+		})
+	}
+}
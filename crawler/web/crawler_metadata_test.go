@@ -0,0 +1,113 @@
+package web_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCrawler_FetchPageMetadata(t *testing.T) {
+	testCases := []struct {
+		name     string
+		page     string
+		expected web.Metadata
+	}{
+		{
+			name: "should parse JSON-LD preferring articles",
+			page: `<html><head>
+				<script type="application/ld+json">{"@context": "https://schema.org", "@graph": [
+					{"@type": "WebSite", "name": "The Go Blog"},
+					{"@type": "BlogPosting", "headline": "An Introduction To Generics",
+					 "author": [{"@type": "Person", "name": "Robert Griesemer"}, {"@type": "Person", "name": "Ian Lance Taylor"}],
+					 "datePublished": "2022-03-22", "dateModified": "2022-03-23T10:00:00+02:00",
+					 "image": {"@type": "ImageObject", "url": "https://go.dev/blog/gopher.png"}}
+				]}</script>
+				<meta property="og:title" content="Generics">
+				<meta property="og:site_name" content="go.dev">
+			</head><body></body></html>`,
+			expected: web.Metadata{
+				web.MetaType:      "BlogPosting",
+				web.MetaTitle:     "An Introduction To Generics",
+				web.MetaAuthor:    "Robert Griesemer, Ian Lance Taylor",
+				web.MetaPublished: "2022-03-22T00:00:00Z",
+				web.MetaModified:  "2022-03-23T10:00:00+02:00",
+				web.MetaImage:     "https://go.dev/blog/gopher.png",
+				web.MetaSiteName:  "go.dev",
+			},
+		},
+		{
+			name: "should parse microdata",
+			page: `<html><body>
+				<article itemscope itemtype="https://schema.org/NewsArticle">
+					<h1 itemprop="headline">Go 1.22 is released</h1>
+					<span itemprop="author" itemscope itemtype="https://schema.org/Person">
+						by <span itemprop="name">Eli Bendersky</span>
+					</span>
+					<time itemprop="datePublished" datetime="2024-02-06T12:00:00Z">February 6</time>
+					<meta itemprop="description" content="Release notes">
+				</article>
+			</body></html>`,
+			expected: web.Metadata{
+				web.MetaType:        "NewsArticle",
+				web.MetaTitle:       "Go 1.22 is released",
+				web.MetaAuthor:      "Eli Bendersky",
+				web.MetaPublished:   "2024-02-06T12:00:00Z",
+				web.MetaDescription: "Release notes",
+			},
+		},
+		{
+			name: "should parse OpenGraph and meta tags",
+			page: `<html><head>
+				<meta property="og:type" content="article">
+				<meta property="og:title" content="Go modules">
+				<meta property="article:published_time" content="2019-03-19T10:00:00-04:00">
+				<meta name="author" content="Tyler Bui-Palsulich">
+			</head><body></body></html>`,
+			expected: web.Metadata{
+				web.MetaType:      "article",
+				web.MetaTitle:     "Go modules",
+				web.MetaAuthor:    "Tyler Bui-Palsulich",
+				web.MetaPublished: "2019-03-19T10:00:00-04:00",
+			},
+		},
+		{
+			name:     "should ignore invalid JSON-LD",
+			page:     `<html><head><script type="application/ld+json">{"@type": </script></head></html>`,
+			expected: web.Metadata{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.page))
+			}))
+			defer server.Close()
+
+			// when
+			page, _, err := web.NewCrawler().FetchPage(server.URL)
+
+			// expected
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, page.Metadata)
+		})
+	}
+}
+
+func TestMetadata_Time(t *testing.T) {
+	m := web.Metadata{web.MetaPublished: "2022-03-22T00:00:00Z", web.MetaModified: "yesterday"}
+
+	published, ok := m.Time(web.MetaPublished)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2022, 3, 22, 0, 0, 0, 0, time.UTC), published)
+
+	_, ok = m.Time(web.MetaModified)
+	assert.False(t, ok)
+	_, ok = m.Time(web.MetaAuthor)
+	assert.False(t, ok)
+}
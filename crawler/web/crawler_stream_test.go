@@ -0,0 +1,127 @@
+package web_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/webtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCrawler_Stream(t *testing.T) {
+	// the base page links to ten pages and a missing one.
+	site := webtest.Site{Pages: map[string]webtest.Page{}}
+	links := make([]string, 0)
+	for i := 0; i < 10; i++ {
+		path := fmt.Sprintf("/%d", i)
+		links = append(links, path)
+		site.Pages[path] = webtest.Page{Text: []string{"page " + path}}
+	}
+	site.Pages["/"] = webtest.Page{Links: append(links, "/missing")}
+
+	t.Run("should stream all pages of the crawl", func(t *testing.T) {
+		// given
+		server := webtest.NewServer(t, site)
+		s := web.NewCrawler()
+
+		// when
+		pages := map[string]web.Page{}
+		failed := make([]string, 0)
+		for r := range s.Stream(context.Background(), server.Link("/")) {
+			if r.Err != nil {
+				failed = append(failed, r.Page.URL)
+				continue
+			}
+			pages[r.Page.URL] = r.Page
+		}
+
+		// expected
+		assert.Len(t, pages, 11)
+		assert.Equal(t, []string{server.Link("/missing")}, failed)
+		assert.Equal(t, []string{"/3"}, pages[server.Link("/3")].Fields[web.FieldAnchors])
+	})
+
+	t.Run("should not fetch ahead of a slow consumer", func(t *testing.T) {
+		// given
+		server := webtest.NewServer(t, site)
+		workers := 2
+		s := web.NewCrawler(web.WithWorkers(workers))
+
+		// when
+		results := s.Stream(context.Background(), server.Link("/"))
+		<-results
+		time.Sleep(100 * time.Millisecond)
+
+		// expected
+		assert.LessOrEqual(t, len(server.Requests()), 1+workers)
+		for range results {
+		}
+		assert.Len(t, server.Requests(), 12)
+	})
+
+	t.Run("should stop the crawl when the context is canceled", func(t *testing.T) {
+		// given
+		server := webtest.NewServer(t, site)
+		o := web.NewProgressObserver(nil)
+		s := web.NewCrawler(web.WithWorkers(1), web.WithObserver(o))
+		ctx, cancel := context.WithCancel(context.Background())
+
+		// when
+		results := s.Stream(ctx, server.Link("/"))
+		<-results
+		cancel()
+		for range results {
+		}
+
+		// expected
+		progress := o.Progress()
+		assert.Less(t, progress.Fetched, 12)
+		assert.Equal(t, 12-progress.Fetched, progress.SkipReasons[web.SkipReasonCanceled])
+	})
+
+	t.Run("should send the error of the base page", func(t *testing.T) {
+		// given
+		server := webtest.NewServer(t, site)
+		s := web.NewCrawler()
+
+		// when
+		results := make([]web.CrawlResult, 0)
+		for r := range s.Stream(context.Background(), server.Link("/missing")) {
+			results = append(results, r)
+		}
+
+		// expected
+		require.Len(t, results, 1)
+		assert.ErrorIs(t, results[0].Err, web.ErrPageDoesNotExist)
+		assert.Equal(t, server.Link("/missing"), results[0].Page.URL)
+	})
+
+	t.Run("should send anchor texts found after the page when the crawl is finished", func(t *testing.T) {
+		// given
+		site := webtest.NewServer(t, webtest.Site{Pages: map[string]webtest.Page{
+			"/":  {Links: []string{"/a", "/b"}},
+			"/a": {Title: "A"},
+			"/b": {HTML: `<html><body><a href="/a">back to A</a><a href="/a">back to A</a></body></html>`},
+		}})
+		s := web.NewCrawler(web.WithWorkers(1))
+
+		// when
+		anchors := map[string][]string{}
+		late := map[string][]string{}
+		for r := range s.Stream(context.Background(), site.Link("/")) {
+			require.NoError(t, r.Err)
+			if len(r.AnchorTexts) != 0 {
+				late[site.Path(r.Page.URL)] = r.AnchorTexts
+				continue
+			}
+			anchors[site.Path(r.Page.URL)] = r.Page.Fields[web.FieldAnchors]
+		}
+
+		// expected
+		assert.Equal(t, []string{"/a"}, anchors["/a"])
+		assert.Equal(t, map[string][]string{"/a": {"back to A"}}, late)
+	})
+}
@@ -1,9 +1,155 @@
 package web_test
 
 import (
+	"errors"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/webtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 )
 
 func TestCrawler_Scrape(t *testing.T) {
 
 }
+
+func TestCrawler_ScrapeMainContent(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><a href="/article">Article</a></body></html>`))
+	})
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(articlePage))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := web.NewCrawler(web.WithExtractionMode(web.ExtractionMainContent))
+
+	// when
+	content, err := s.Scrape(server.URL + "/")
+
+	// expected
+	require.NoError(t, err)
+	require.Contains(t, content, server.URL+"/article")
+	article := content[server.URL+"/article"]
+	assert.Equal(t, "Tutorial: Getting started with generics", article[0])
+	assert.NotContains(t, article, "Privacy policy")
+	assert.NotContains(t, article, "Home")
+}
+
+func TestCrawler_ScrapePagesFields(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html>
+		<head>
+			<title>Go tour</title>
+			<meta name="description" content="An interactive introduction to Go">
+			<meta name="keywords" content="go, tour">
+			<meta name="viewport" content="width=device-width">
+		</head>
+		<body>
+			<h1>Welcome</h1>
+			<p>Take the tour.</p>
+			<h2>Basics</h2>
+			<img src="/gopher.png" alt="Gopher" title="Mascot">
+			<a href="/basics">Packages and variables</a>
+		</body>
+		</html>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := web.NewCrawler()
+
+	// when
+	pages, err := s.ScrapePages(server.URL + "/")
+
+	// expected
+	require.NoError(t, err)
+	page := pages[server.URL+"/"]
+	assert.Equal(t, "Go tour", page.Title)
+	assert.Equal(t, map[string][]string{
+		web.FieldTitle:    {"Go tour"},
+		web.FieldHeadings: {"Welcome", "Basics"},
+		web.FieldBody:     {"Go tour", "Welcome", "Take the tour.", "Basics", "Packages and variables"},
+		web.FieldLinks:    {"Packages and variables"},
+		web.FieldAlt:      {"Gopher", "Mascot"},
+		web.FieldMeta:     {"An interactive introduction to Go", "go, tour"},
+	}, page.Fields)
+}
+
+func TestCrawler_ScrapePagesAnchors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body>
+			<a href="/tour">Go   tour</a>
+			<a href="/tour#welcome">Tour of <b>Go</b></a>
+			<a href="/tour">Go tour</a>
+			<a href="/play"><img src="/gopher.png"></a>
+		</body></html>`))
+	})
+	mux.HandleFunc("/tour", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><p>Welcome!</p></body></html>`))
+	})
+	mux.HandleFunc("/play", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><p>Playground</p></body></html>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := web.NewCrawler()
+
+	// when
+	pages, err := s.ScrapePages(server.URL + "/")
+
+	// expected
+	require.NoError(t, err)
+	require.Len(t, pages, 3)
+	assert.Equal(t, []string{"Go tour", "Tour of Go"}, pages[server.URL+"/tour"].Fields[web.FieldAnchors])
+	assert.Equal(t, []string{"Go tour", "Tour of Go"}, s.AnchorTexts(server.URL+"/tour"))
+	assert.NotContains(t, pages[server.URL+"/play"].Fields, web.FieldAnchors)
+	assert.Equal(t, map[string][]string{
+		server.URL + "/": {server.URL + "/tour", server.URL + "/play"},
+	}, s.LinkGraph().Edges())
+}
+
+func TestCrawler_ScrapePagesCrawls(t *testing.T) {
+	site := func(name string) *webtest.Server {
+		return webtest.NewServer(t, webtest.Site{Pages: map[string]webtest.Page{
+			"/":               {Links: []string{"/" + name + "/1", "/" + name + "/2"}},
+			"/" + name + "/1": {Title: name + "1"},
+			"/" + name + "/2": {Title: name + "2"},
+		}})
+	}
+	a, b := site("a"), site("b")
+	s := web.NewCrawler(web.WithWorkers(1))
+
+	// when
+	pages := make([]map[string]web.Page, 2)
+	errs := make([]error, 2)
+	wg := &sync.WaitGroup{}
+	for i, server := range []*webtest.Server{a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pages[i], errs[i] = s.ScrapePages(server.Link("/"))
+		}()
+	}
+	wg.Wait()
+	_, err := s.ScrapePages(b.Link("/"))
+
+	// expected
+	require.NoError(t, errors.Join(append(errs, err)...))
+	assert.Len(t, pages[0], 3)
+	assert.Len(t, pages[1], 3)
+	webtest.AssertVisited(t, a, "/", "/a/1", "/a/2")
+	assert.Equal(t, map[string][]string{
+		b.Link("/"): {b.Link("/b/1"), b.Link("/b/2")},
+	}, s.LinkGraph().Edges())
+	assert.Equal(t, []string{"/b/1"}, s.AnchorTexts(b.Link("/b/1")))
+	assert.Empty(t, s.AnchorTexts(a.Link("/a/1")))
+}
@@ -0,0 +1,172 @@
+package web
+
+import (
+	gorecslices "github.com/mishaprokop4ik/gorecs-search/pkg/slices"
+	"strings"
+)
+
+// Names of the Page Fields filled for every extracted page.
+const (
+	// FieldTitle is the text of the <title> tag or of the Profile title selector.
+	FieldTitle = "title"
+	// FieldHeadings are texts of h1-h6 tags.
+	FieldHeadings = "headings"
+	// FieldBody is the page content, the same as Page.Content.
+	FieldBody = "body"
+	// FieldLinks are texts of links placed on the page.
+	FieldLinks = "links"
+	// FieldAlt are alt and title attributes of images.
+	FieldAlt = "alt"
+	// FieldMeta are description and keywords meta tags.
+	FieldMeta = "meta"
+	// FieldAnchors are texts of links to the page found on other crawled pages.
+	FieldAnchors = "anchors"
+)
+
+var headingTags = []string{"h1", "h2", "h3", "h4", "h5", "h6"}
+
+var (
+	titleSelector = MustCompileSelector("title")
+	metaSelector  = MustCompileSelector("meta[name][content]")
+)
+
+// metaFieldNames are names of meta tags stored in FieldMeta.
+var metaFieldNames = []string{"description", "keywords"}
+
+// extractPage fills Page content and fields from the page tree.
+//
+// Content is taken from the profile Include selectors, from MainContent for ExtractionMainContent mode
+// or from the whole page otherwise.
+func extractPage(root *Tag, profile *compiledProfile, mode ExtractionMode, page *Page) {
+	scopes := []*Tag{root}
+	switch {
+	case profile != nil && len(profile.Include) != 0:
+		scopes = outermost(root.Select(profile.include))
+	case mode == ExtractionMainContent:
+		scopes = []*Tag{MainContent(root)}
+	}
+
+	excluded := profile.excluded(root)
+	skip := func(tag *Tag) bool {
+		if excluded[tag] {
+			return true
+		}
+		if mode == ExtractionMainContent {
+			return isNoise(tag)
+		}
+		// images don't have text, but their attributes are extracted.
+		return tag.Name != "img" && isFilteredByDefault(tag)
+	}
+
+	fields := map[string][]string{}
+	add := func(field, text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			fields[field] = append(fields[field], text)
+		}
+	}
+
+	for _, scope := range scopes {
+		page.Content = append(page.Content, collectText(scope, skip)...)
+
+		walkContent(scope, skip, func(tag *Tag) {
+			switch {
+			case gorecslices.Exist(tag.Name, headingTags):
+				add(FieldHeadings, tag.Text())
+			case tag.Name == htmlLinkTag:
+				add(FieldLinks, tag.Text())
+			case tag.Name == "img":
+				add(FieldAlt, tag.Attributes["alt"])
+				add(FieldAlt, tag.Attributes["title"])
+			}
+		})
+	}
+	fields[FieldBody] = page.Content
+
+	if profile != nil && profile.Title != "" {
+		if titles := root.Select(profile.title); len(titles) != 0 {
+			page.Title = titles[0].Text()
+		}
+	}
+	if page.Title == "" {
+		if titles := root.Select(titleSelector); len(titles) != 0 {
+			page.Title = titles[0].Text()
+		}
+	}
+	add(FieldTitle, page.Title)
+
+	for _, meta := range root.Select(metaSelector) {
+		if gorecslices.Exist(strings.ToLower(meta.Attributes["name"]), metaFieldNames) {
+			add(FieldMeta, meta.Attributes["content"])
+		}
+	}
+
+	if profile != nil {
+		for name, selector := range profile.fields {
+			for _, tag := range root.Select(selector) {
+				add(name, tag.Text())
+			}
+		}
+	}
+
+	page.Fields = fields
+}
+
+// walkContent calls fn for all elements inside the Tag, subtrees of tags reported by skip are ignored.
+func walkContent(tag *Tag, skip func(tag *Tag) bool, fn func(tag *Tag)) {
+	tag.Walk(func(t *Tag) bool {
+		if !t.IsElement() {
+			return true
+		}
+		if t != tag && skip(t) {
+			return false
+		}
+
+		fn(t)
+		return true
+	})
+}
+
+// collectText returns text blocks inside the Tag, subtrees of tags reported by skip are ignored.
+func collectText(tag *Tag, skip func(tag *Tag) bool) []string {
+	result := make([]string, 0)
+	tag.Walk(func(t *Tag) bool {
+		if t != tag && t.IsElement() && skip(t) {
+			return false
+		}
+		if t.Type == Body {
+			if text := strings.TrimSpace(t.Body); text != "" {
+				result = append(result, text)
+			}
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// outermost drops tags which are descendants of other tags from the list.
+func outermost(tags []*Tag) []*Tag {
+	set := make(map[*Tag]bool, len(tags))
+	for _, tag := range tags {
+		set[tag] = true
+	}
+
+	result := make([]*Tag, 0, len(tags))
+	for _, tag := range tags {
+		nested := false
+		for parent := tag.Parent; parent != nil && !nested; parent = parent.Parent {
+			nested = set[parent]
+		}
+		if !nested {
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+// isFilteredByDefault reports whether the Tag is dropped by DefaultContentFilterOption.
+func isFilteredByDefault(tag *Tag) bool {
+	return gorecslices.Exist(tag.Name, DefaultContentFilterOption().Tags)
+}
@@ -0,0 +1,128 @@
+package web
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"net/url"
+	"strings"
+)
+
+// SkipReasonIrrelevant is reported by Observer.OnSkipped for links pruned by a focused crawl.
+const SkipReasonIrrelevant = "irrelevant to the topic"
+
+// DefaultParentWeight takes half of a link score from its page and half from its anchor text.
+const DefaultParentWeight = 0.5
+
+// Focus describes a topic of a focused crawl.
+//
+// Every fetched page is scored against Query with ranker.Model.Relevance.
+// A link is scored by the relevance of the page it's found on and by its anchor text and URL,
+// links with the score below Threshold aren't followed, the rest are fetched in the score order.
+type Focus struct {
+	// Query is the topic, e.g. "generics".
+	Query string
+	// Threshold is the minimal link score in [0, 1) to follow the link.
+	Threshold float64
+	// ParentWeight is a part of the link score taken from the relevance of the page the link is found on,
+	// the rest is taken from the anchor text. Zero means DefaultParentWeight.
+	ParentWeight float64
+}
+
+// WithFocus turns on a focused crawl, links are fetched in the order of their scores by a PriorityFrontier.
+// When a Frontier is set by WithFrontier, it orders links and the focus only prunes irrelevant ones.
+func WithFocus(focus Focus) CrawlerOption {
+	return func(c *Crawler) {
+		c.focus = &focus
+	}
+}
+
+// focuser scores pages and links of a single focused crawl. It's used only by the crawl loop, so it isn't synchronized.
+type focuser struct {
+	Focus
+
+	terms     []string
+	pages     *ranker.Model
+	anchors   *ranker.Model
+	relevance map[string]float64
+	// scores are scores of links computed by prune, they are taken by score when links are pushed to the Frontier.
+	scores map[string]float64
+}
+
+func newFocuser(focus Focus) *focuser {
+	if focus.ParentWeight == 0 {
+		focus.ParentWeight = DefaultParentWeight
+	}
+
+	return &focuser{
+		Focus:     focus,
+		terms:     lexer.NewLexer(focus.Query).All(),
+		pages:     ranker.NewModel(map[string][]string{}),
+		anchors:   ranker.NewModel(map[string][]string{}),
+		relevance: map[string]float64{},
+		scores:    map[string]float64{},
+	}
+}
+
+// add indexes the fetched page and returns its relevance to the topic.
+func (f *focuser) add(page Page) float64 {
+	fields := make(map[ranker.Field][]string, len(page.Fields))
+	for name, text := range page.Fields {
+		fields[ranker.Field(name)] = text
+	}
+	f.pages.Index(ranker.Document{Path: page.URL, Fields: fields})
+
+	relevance := f.pages.Relevance(ranker.Path(page.URL), f.terms...)
+	f.relevance[page.URL] = relevance
+
+	return relevance
+}
+
+// score returns the priority of the link, the score computed by prune is returned without computing it again.
+func (f *focuser) score(link Link) float64 {
+	if score, ok := f.scores[link.URL]; ok {
+		delete(f.scores, link.URL)
+		return score
+	}
+
+	return f.linkScore(link)
+}
+
+// linkScore computes the score of the link, seeds without a source page are scored by their anchor text only.
+func (f *focuser) linkScore(link Link) float64 {
+	f.anchors.Index(ranker.Document{
+		Path: link.URL,
+		Fields: map[ranker.Field][]string{
+			ranker.FieldAnchors: {link.AnchorText},
+			ranker.FieldLinks:   {urlWords(link.URL)},
+		},
+	})
+	anchor := f.anchors.Relevance(ranker.Path(link.URL), f.terms...)
+	delete(f.anchors.Docs, ranker.Path(link.URL))
+
+	if link.Source == "" {
+		return anchor
+	}
+
+	return f.ParentWeight*f.relevance[link.Source] + (1-f.ParentWeight)*anchor
+}
+
+// prune reports whether the link isn't followed, the score of a followed link is kept for score.
+func (f *focuser) prune(link Link) bool {
+	score := f.linkScore(link)
+	if score < f.Threshold {
+		return true
+	}
+	f.scores[link.URL] = score
+
+	return false
+}
+
+// urlWords returns the path and query of the link, e.g. "/doc/tutorial/generics" for the generics tutorial.
+func urlWords(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return strings.Join([]string{u.Path, u.RawQuery}, " ")
+}
@@ -0,0 +1,117 @@
+package web_test
+
+import (
+	"errors"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/webtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestCrawler_ScrapePagesFocus(t *testing.T) {
+	site := webtest.Site{Pages: map[string]webtest.Page{
+		"/": {HTML: `<html><head><title>Go documentation</title></head><body>
+			<a href="/modules">Modules reference</a>
+			<a href="/blog">Blog</a>
+			<a href="/generics">Generics tutorial</a>
+		</body></html>`},
+		"/generics": {HTML: `<html><head><title>Tutorial: getting started with generics</title></head><body>
+			<p>With generics you can declare functions that work with any of a set of types.</p>
+			<a href="/about">About</a>
+			<a href="/generics/constraints">Generics constraints</a>
+		</body></html>`},
+		"/generics/constraints": {HTML: `<html><head><title>Constraints</title></head><body>Type sets.</body></html>`},
+		"/about":                {HTML: `<html><head><title>About</title></head><body>About Go.</body></html>`},
+		"/modules":              {HTML: `<html><head><title>Modules</title></head><body>Go modules.</body></html>`},
+		"/blog":                 {HTML: `<html><head><title>Blog</title></head><body>News.</body></html>`},
+	}}
+
+	testCases := []struct {
+		name     string
+		options  []web.CrawlerOption
+		expected []string
+	}{
+		{
+			name:     "should fetch relevant links first and follow links of relevant pages",
+			options:  []web.CrawlerOption{web.WithFocus(web.Focus{Query: "generics", Threshold: 0.3, ParentWeight: 0.5})},
+			expected: []string{"/", "/generics", "/generics/constraints", "/about"},
+		},
+		{
+			name:     "should use the default parent weight",
+			options:  []web.CrawlerOption{web.WithFocus(web.Focus{Query: "generics", Threshold: 0.3})},
+			expected: []string{"/", "/generics", "/generics/constraints", "/about"},
+		},
+		{
+			name: "should keep the frontier set before the focus",
+			options: []web.CrawlerOption{
+				web.WithFrontier(func() web.Frontier { return web.NewBFSFrontier() }),
+				web.WithFocus(web.Focus{Query: "generics", Threshold: 0.3}),
+			},
+			expected: []string{"/", "/generics", "/about", "/generics/constraints"},
+		},
+		{
+			name: "should keep the frontier set after the focus",
+			options: []web.CrawlerOption{
+				web.WithFocus(web.Focus{Query: "generics", Threshold: 0.3}),
+				web.WithFrontier(func() web.Frontier { return web.NewBFSFrontier() }),
+			},
+			expected: []string{"/", "/generics", "/about", "/generics/constraints"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			server := webtest.NewServer(t, site)
+			o := &fetchOrderObserver{}
+			s := web.NewCrawler(append(tc.options, web.WithWorkers(1), web.WithMaxDepth(2), web.WithObserver(o))...)
+
+			// when
+			pages, err := s.ScrapePages(server.Link("/"))
+
+			// expected
+			require.NoError(t, err)
+			webtest.AssertVisitedInOrder(t, server, tc.expected...)
+			for i := range o.skipped {
+				o.skipped[i] = server.Path(o.skipped[i])
+			}
+			assert.ElementsMatch(t, []string{
+				"/modules: " + web.SkipReasonIrrelevant,
+				"/blog: " + web.SkipReasonIrrelevant,
+			}, o.skipped)
+
+			assert.Greater(t, pages[server.Link("/generics")].Relevance, pages[server.Link("/")].Relevance)
+			assert.Greater(t, pages[server.Link("/")].Relevance, pages[server.Link("/about")].Relevance)
+		})
+	}
+
+	t.Run("should score pages of every crawl alone", func(t *testing.T) {
+		// given
+		server := webtest.NewServer(t, site)
+		focus := web.WithFocus(web.Focus{Query: "generics", Threshold: 0.3})
+		expected, err := web.NewCrawler(focus, web.WithMaxDepth(2)).ScrapePages(server.Link("/"))
+		require.NoError(t, err)
+		s := web.NewCrawler(focus, web.WithMaxDepth(2))
+
+		// when
+		pages := make([]map[string]web.Page, 4)
+		errs := make([]error, len(pages))
+		wg := &sync.WaitGroup{}
+		for i := range pages {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				pages[i], errs[i] = s.ScrapePages(server.Link("/"))
+			}()
+		}
+		wg.Wait()
+
+		// expected
+		require.NoError(t, errors.Join(errs...))
+		for _, crawled := range pages {
+			assert.Equal(t, expected, crawled)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package web
+
+import (
+	"container/heap"
+	"fmt"
+	"regexp"
+)
+
+// Link is a URL waiting in a Frontier to be fetched.
+type Link struct {
+	URL string
+	// Source is the URL of the page the link is found on, empty for the base page.
+	Source string
+	// AnchorText is the text of the link on the Source page.
+	AnchorText string
+	// Depth is a number of links from the base page.
+	Depth int
+}
+
+// Frontier decides which Link is fetched next.
+//
+// Frontier is used by one crawl at a time and isn't required to be safe for concurrent use.
+type Frontier interface {
+	Push(link Link)
+	// Pop returns the next Link to fetch, false is returned when the Frontier is empty.
+	Pop() (Link, bool)
+	Len() int
+}
+
+// BFSFrontier fetches links in the order they are found, so pages closer to the base page are fetched first.
+type BFSFrontier struct {
+	links []Link
+}
+
+func NewBFSFrontier() *BFSFrontier {
+	return &BFSFrontier{links: make([]Link, 0)}
+}
+
+func (f *BFSFrontier) Push(link Link) {
+	f.links = append(f.links, link)
+}
+
+func (f *BFSFrontier) Pop() (Link, bool) {
+	if len(f.links) == 0 {
+		return Link{}, false
+	}
+
+	link := f.links[0]
+	f.links = f.links[1:]
+
+	return link, true
+}
+
+func (f *BFSFrontier) Len() int {
+	return len(f.links)
+}
+
+// DFSFrontier fetches the most recently found link first, so the crawl goes deep before it goes wide.
+type DFSFrontier struct {
+	links []Link
+}
+
+func NewDFSFrontier() *DFSFrontier {
+	return &DFSFrontier{links: make([]Link, 0)}
+}
+
+func (f *DFSFrontier) Push(link Link) {
+	f.links = append(f.links, link)
+}
+
+func (f *DFSFrontier) Pop() (Link, bool) {
+	if len(f.links) == 0 {
+		return Link{}, false
+	}
+
+	link := f.links[len(f.links)-1]
+	f.links = f.links[:len(f.links)-1]
+
+	return link, true
+}
+
+func (f *DFSFrontier) Len() int {
+	return len(f.links)
+}
+
+// ScoreFunc returns a priority of the Link, links with a higher score are fetched first.
+type ScoreFunc func(link Link) float64
+
+// PriorityFrontier fetches links with the highest score first.
+// Links with equal scores are fetched in the order they are found.
+type PriorityFrontier struct {
+	score ScoreFunc
+	queue *linkQueue
+	count uint64
+}
+
+func NewPriorityFrontier(score ScoreFunc) *PriorityFrontier {
+	return &PriorityFrontier{score: score, queue: &linkQueue{}}
+}
+
+func (f *PriorityFrontier) Push(link Link) {
+	heap.Push(f.queue, scoredLink{link: link, score: f.score(link), order: f.count})
+	f.count++
+}
+
+func (f *PriorityFrontier) Pop() (Link, bool) {
+	if f.queue.Len() == 0 {
+		return Link{}, false
+	}
+
+	scored, _ := heap.Pop(f.queue).(scoredLink)
+	return scored.link, true
+}
+
+func (f *PriorityFrontier) Len() int {
+	return f.queue.Len()
+}
+
+type scoredLink struct {
+	link  Link
+	score float64
+	order uint64
+}
+
+// linkQueue implements heap.Interface with the highest score on top.
+type linkQueue []scoredLink
+
+func (q linkQueue) Len() int { return len(q) }
+
+func (q linkQueue) Less(i, j int) bool {
+	if q[i].score != q[j].score {
+		return q[i].score > q[j].score
+	}
+
+	return q[i].order < q[j].order
+}
+
+func (q linkQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *linkQueue) Push(x any) {
+	link, _ := x.(scoredLink)
+	*q = append(*q, link)
+}
+
+func (q *linkQueue) Pop() any {
+	old := *q
+	link := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return link
+}
+
+// ScoreByDepth prefers links closer to the base page.
+func ScoreByDepth() ScoreFunc {
+	return func(link Link) float64 {
+		return -float64(link.Depth)
+	}
+}
+
+// ScoreByPriorities scores links by known priorities, e.g. <priority> of sitemap entries.
+// Links without priority get defaultPriority.
+func ScoreByPriorities(priorities map[string]float64, defaultPriority float64) ScoreFunc {
+	return func(link Link) float64 {
+		if priority, ok := priorities[link.URL]; ok {
+			return priority
+		}
+
+		return defaultPriority
+	}
+}
+
+// ScoreByURLPatterns scores links by regular expressions of their URLs.
+// Scores of all matched patterns are summed up.
+func ScoreByURLPatterns(patterns map[string]float64) (ScoreFunc, error) {
+	type pattern struct {
+		re    *regexp.Regexp
+		score float64
+	}
+
+	compiled := make([]pattern, 0, len(patterns))
+	for p, score := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, pattern{re: re, score: score})
+	}
+
+	return func(link Link) float64 {
+		score := float64(0)
+		for _, p := range compiled {
+			if p.re.MatchString(link.URL) {
+				score += p.score
+			}
+		}
+
+		return score
+	}, nil
+}
+
+// SumScores returns a ScoreFunc which sums up scores of all functions.
+func SumScores(scores ...ScoreFunc) ScoreFunc {
+	return func(link Link) float64 {
+		result := float64(0)
+		for _, score := range scores {
+			result += score(link)
+		}
+
+		return result
+	}
+}
@@ -0,0 +1,185 @@
+package web_test
+
+import (
+	"fmt"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func popAll(f web.Frontier) []string {
+	result := make([]string, 0)
+	for link, ok := f.Pop(); ok; link, ok = f.Pop() {
+		result = append(result, link.URL)
+	}
+
+	return result
+}
+
+func TestFrontier(t *testing.T) {
+	links := []web.Link{
+		{URL: "/a", Depth: 1},
+		{URL: "/b", Depth: 2},
+		{URL: "/blog/c", Depth: 1},
+		{URL: "/d", Depth: 1},
+	}
+	urlScore, err := web.ScoreByURLPatterns(map[string]float64{"^/blog/": 10})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		frontier web.Frontier
+		expected []string
+	}{
+		{
+			name:     "should pop links in push order",
+			frontier: web.NewBFSFrontier(),
+			expected: []string{"/a", "/b", "/blog/c", "/d"},
+		},
+		{
+			name:     "should pop the last pushed link first",
+			frontier: web.NewDFSFrontier(),
+			expected: []string{"/d", "/blog/c", "/b", "/a"},
+		},
+		{
+			name:     "should pop less deep links first keeping push order for equal scores",
+			frontier: web.NewPriorityFrontier(web.ScoreByDepth()),
+			expected: []string{"/a", "/blog/c", "/d", "/b"},
+		},
+		{
+			name: "should pop links by summed up scores",
+			frontier: web.NewPriorityFrontier(web.SumScores(
+				web.ScoreByDepth(),
+				urlScore,
+				web.ScoreByPriorities(map[string]float64{"/b": 5}, 0),
+			)),
+			expected: []string{"/blog/c", "/b", "/a", "/d"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, link := range links {
+				tc.frontier.Push(link)
+			}
+
+			assert.Equal(t, len(links), tc.frontier.Len())
+			assert.Equal(t, tc.expected, popAll(tc.frontier))
+			assert.Equal(t, 0, tc.frontier.Len())
+		})
+	}
+}
+
+type fetchOrderObserver struct {
+	web.NopObserver
+
+	mutex   sync.Mutex
+	fetched []string
+	skipped []string
+}
+
+func (o *fetchOrderObserver) OnFetchStart(url string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.fetched = append(o.fetched, url)
+}
+
+func (o *fetchOrderObserver) OnSkipped(url string, reason string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.skipped = append(o.skipped, url+": "+reason)
+}
+
+func TestCrawler_ScrapePagesFrontier(t *testing.T) {
+	// every page links to two pages one level deeper: / -> /0, /1; /0 -> /00, /01 and so on.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		_, _ = fmt.Fprintf(w, `<html><body><a href="%[1]s/0">0</a><a href="%[1]s/1">1</a></body></html>`, path)
+	}))
+	defer server.Close()
+
+	strip := func(urls []string) []string {
+		for i := range urls {
+			urls[i] = strings.TrimPrefix(urls[i], server.URL)
+		}
+		return urls
+	}
+
+	moreOnes, err := web.ScoreByURLPatterns(map[string]float64{"/1$": 1})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		frontier func() web.Frontier
+		fetched  []string
+		skipped  []string
+	}{
+		{
+			name:     "should crawl breadth first",
+			frontier: func() web.Frontier { return web.NewBFSFrontier() },
+			fetched:  []string{"/", "/0", "/1", "/0/0", "/0/1"},
+			skipped:  []string{"/1/0", "/1/1"},
+		},
+		{
+			name:     "should crawl depth first",
+			frontier: func() web.Frontier { return web.NewDFSFrontier() },
+			fetched:  []string{"/", "/1", "/1/1", "/1/0", "/0"},
+			skipped:  []string{"/0/1", "/0/0"},
+		},
+		{
+			name:     "should crawl links with the highest score first",
+			frontier: func() web.Frontier { return web.NewPriorityFrontier(moreOnes) },
+			fetched:  []string{"/", "/1", "/1/1", "/0", "/0/1"},
+			skipped:  []string{"/1/0", "/0/0"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &fetchOrderObserver{}
+			s := web.NewCrawler(
+				web.WithFrontier(tc.frontier),
+				web.WithWorkers(1),
+				web.WithMaxDepth(2),
+				web.WithMaxPages(5),
+				web.WithObserver(o),
+			)
+
+			// when
+			pages, err := s.ScrapePages(server.URL + "/")
+
+			// expected
+			require.NoError(t, err)
+			assert.Len(t, pages, 5)
+			assert.Equal(t, tc.fetched, strip(o.fetched))
+			for i := range tc.skipped {
+				tc.skipped[i] += ": " + web.SkipReasonPageBudget
+			}
+			assert.Equal(t, tc.skipped, strip(o.skipped))
+		})
+	}
+
+	t.Run("should fetch pages concurrently", func(t *testing.T) {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			path := strings.TrimSuffix(r.URL.Path, "/")
+			_, _ = fmt.Fprintf(w, `<html><body><a href="%[1]s/0">0</a><a href="%[1]s/1">1</a></body></html>`, path)
+		}))
+		defer slow.Close()
+
+		s := web.NewCrawler(web.WithWorkers(4), web.WithMaxDepth(2))
+
+		start := time.Now()
+		pages, err := s.ScrapePages(slow.URL + "/")
+
+		require.NoError(t, err)
+		assert.Len(t, pages, 7)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
@@ -0,0 +1,49 @@
+package web
+
+import (
+	gorecslices "github.com/mishaprokop4ik/gorecs-search/pkg/slices"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// LinkGraph is a directed graph of links between crawled pages.
+type LinkGraph struct {
+	mutex *sync.RWMutex
+	links map[string][]string
+}
+
+func NewLinkGraph() *LinkGraph {
+	return &LinkGraph{mutex: &sync.RWMutex{}, links: map[string][]string{}}
+}
+
+// AddLink adds an edge from the source page URL to the target one.
+func (g *LinkGraph) AddLink(source, target string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if !gorecslices.Exist(target, g.links[source]) {
+		g.links[source] = append(g.links[source], target)
+	}
+}
+
+// Links returns targets of all links from the source page URL.
+func (g *LinkGraph) Links(source string) []string {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return slices.Clone(g.links[source])
+}
+
+// Edges returns a copy of the graph as targets by the source page URL.
+func (g *LinkGraph) Edges() map[string][]string {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	edges := maps.Clone(g.links)
+	for source, targets := range edges {
+		edges[source] = slices.Clone(targets)
+	}
+
+	return edges
+}
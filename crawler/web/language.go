@@ -0,0 +1,25 @@
+package web
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/language"
+	"net/http"
+	"strings"
+)
+
+var htmlLangSelector = MustCompileSelector("html[lang]")
+
+// detectLanguage identifies the page language by its text.
+// When the text is too short or of an unknown language, the <html lang> attribute and the Content-Language header are used.
+func detectLanguage(page Page, root *Tag, header http.Header) string {
+	if lang := language.Detect(page.Title + " " + strings.Join(page.Content, " ")); lang != language.Unknown {
+		return lang
+	}
+
+	if tags := root.Select(htmlLangSelector); len(tags) != 0 {
+		if lang := language.Normalize(tags[0].Attributes["lang"]); lang != "" {
+			return lang
+		}
+	}
+
+	return language.Normalize(header.Get("Content-Language"))
+}
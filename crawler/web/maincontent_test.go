@@ -0,0 +1,68 @@
+package web_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html lang="en">
+<head><title>Generics tutorial</title><style>body { color: red; }</style></head>
+<body>
+	<div id="cookie-banner">We use cookies to improve your experience. Read our privacy policy, accept all cookies.</div>
+	<header class="site-header">
+		<nav><ul><li><a href="/">Home</a></li><li><a href="/blog">Blog</a></li><li><a href="/privacy">Privacy policy</a></li></ul></nav>
+	</header>
+	<div class="layout">
+		<div class="sidebar">
+			<ul>
+				<li><a href="/a">Some related post about a completely different topic</a></li>
+				<li><a href="/b">Another related post, with a long title, about other things</a></li>
+			</ul>
+		</div>
+		<div class="post-content">
+			<h1>Tutorial: Getting started with generics</h1>
+			<p>This tutorial introduces the basics of generics in Go. With generics, you can declare and use functions or types that are written to work with any of a set of types provided by calling code.</p>
+			<p>In this tutorial, you'll declare two simple non-generic functions, then capture the same logic in a single generic function.</p>
+			<div class="share"><a href="/share">Share on social networks</a></div>
+			<p>You'll progress through the following sections: create a folder for your code, add non-generic functions, add a generic function to handle multiple types.</p>
+		</div>
+	</div>
+	<footer><p>Copyright 2024, all rights reserved, privacy policy, terms of service and more.</p></footer>
+	<script>console.log("tracking, analytics, and more")</script>
+</body>
+</html>`
+
+func TestMainContent(t *testing.T) {
+	c := web.NewClient(web.BaseRetryPolicy(), 5)
+	root, err := c.ParseTree(io.NopCloser(strings.NewReader(articlePage)))
+	require.NoError(t, err)
+
+	// when
+	content := web.MainContent(root)
+	text := web.ContentText(content)
+
+	// expected
+	assert.True(t, content.HasClass("post-content"))
+	assert.Equal(t, []string{
+		"Tutorial: Getting started with generics",
+		"This tutorial introduces the basics of generics in Go. With generics, you can declare and use functions " +
+			"or types that are written to work with any of a set of types provided by calling code.",
+		"In this tutorial, you'll declare two simple non-generic functions, " +
+			"then capture the same logic in a single generic function.",
+		"You'll progress through the following sections: create a folder for your code, " +
+			"add non-generic functions, add a generic function to handle multiple types.",
+	}, text)
+}
+
+func TestMainContent_NoCandidates(t *testing.T) {
+	c := web.NewClient(web.BaseRetryPolicy(), 5)
+	root, err := c.ParseTree(io.NopCloser(strings.NewReader(`<html><body><a href="/">Home</a></body></html>`)))
+	require.NoError(t, err)
+
+	assert.Same(t, root, web.MainContent(root))
+}
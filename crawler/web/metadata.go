@@ -0,0 +1,263 @@
+package web
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Keys of the page Metadata.
+const (
+	MetaTitle       = "title"
+	MetaAuthor      = "author"
+	MetaDescription = "description"
+	MetaType        = "type"
+	MetaImage       = "image"
+	MetaSiteName    = "site_name"
+	// MetaPublished and MetaModified are RFC 3339 dates when they can be parsed.
+	MetaPublished = "published"
+	MetaModified  = "modified"
+)
+
+// Metadata is structured data of a page normalized from JSON-LD, microdata and OpenGraph by Meta keys.
+type Metadata map[string]string
+
+// Time returns the date by the key, e.g. MetaPublished, false is returned when it's missing or can't be parsed.
+func (m Metadata) Time(key string) (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, m[key])
+
+	return t, err == nil
+}
+
+var (
+	jsonLDSelector     = MustCompileSelector(`script[type="application/ld+json"]`)
+	itemScopeSelector  = MustCompileSelector("[itemscope]")
+	openGraphSelector  = MustCompileSelector("meta[property][content]")
+	metaAuthorSelector = MustCompileSelector(`meta[name="author"][content]`)
+)
+
+// openGraphKeys are Metadata keys by OpenGraph properties.
+var openGraphKeys = map[string]string{
+	"og:title":               MetaTitle,
+	"og:description":         MetaDescription,
+	"og:type":                MetaType,
+	"og:image":               MetaImage,
+	"og:site_name":           MetaSiteName,
+	"article:author":         MetaAuthor,
+	"article:published_time": MetaPublished,
+	"article:modified_time":  MetaModified,
+}
+
+// schemaProperties are schema.org properties used by JSON-LD and microdata in the order of preference,
+// headline goes before name, as name of an article is often a short version of its headline.
+var schemaProperties = []string{"headline", "name", "author", "description", "image", "datePublished", "dateModified"}
+
+// schemaKeys are Metadata keys by schemaProperties.
+var schemaKeys = map[string]string{
+	"headline":      MetaTitle,
+	"name":          MetaTitle,
+	"author":        MetaAuthor,
+	"description":   MetaDescription,
+	"image":         MetaImage,
+	"datePublished": MetaPublished,
+	"dateModified":  MetaModified,
+}
+
+// dateLayouts are layouts of dates found in structured data.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// extractMetadata returns structured data of the page.
+// JSON-LD is preferred over microdata, and microdata is preferred over OpenGraph and meta tags.
+func extractMetadata(root *Tag) Metadata {
+	m := Metadata{}
+	set := func(key, value string) {
+		value = strings.Join(strings.Fields(value), " ")
+		if _, ok := m[key]; !ok && value != "" {
+			if key == MetaPublished || key == MetaModified {
+				value = normalizeDate(value)
+			}
+			m[key] = value
+		}
+	}
+
+	for _, entity := range jsonLDEntities(root) {
+		setSchemaEntity(entity, set)
+	}
+
+	for _, scope := range outermost(root.Select(itemScopeSelector)) {
+		set(MetaType, schemaType(scope.Attributes["itemtype"]))
+		props := microdataProperties(scope)
+		for _, name := range schemaProperties {
+			set(schemaKeys[name], props[name])
+		}
+	}
+
+	for _, meta := range root.Select(openGraphSelector) {
+		if key, ok := openGraphKeys[strings.ToLower(meta.Attributes["property"])]; ok {
+			set(key, meta.Attributes["content"])
+		}
+	}
+	for _, meta := range root.Select(metaAuthorSelector) {
+		set(MetaAuthor, meta.Attributes["content"])
+	}
+
+	return m
+}
+
+// jsonLDEntities returns objects of all JSON-LD scripts, articles go first as they describe the page itself.
+func jsonLDEntities(root *Tag) []map[string]any {
+	entities := make([]map[string]any, 0)
+	var collect func(v any)
+	collect = func(v any) {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				collect(item)
+			}
+		case map[string]any:
+			if graph, ok := v["@graph"]; ok {
+				collect(graph)
+				return
+			}
+			entities = append(entities, v)
+		}
+	}
+
+	for _, script := range root.Select(jsonLDSelector) {
+		var v any
+		if err := json.Unmarshal([]byte(script.Text()), &v); err == nil {
+			collect(v)
+		}
+	}
+
+	slices.SortStableFunc(entities, func(a, b map[string]any) int {
+		return articleOrder(a) - articleOrder(b)
+	})
+
+	return entities
+}
+
+func setSchemaEntity(entity map[string]any, set func(key, value string)) {
+	set(MetaType, jsonString(entity["@type"]))
+	for _, name := range schemaProperties {
+		set(schemaKeys[name], jsonString(entity[name]))
+	}
+}
+
+// jsonString returns a text of a JSON-LD value: a string, the name or url of an object or texts of an array.
+func jsonString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any:
+		if name := jsonString(v["name"]); name != "" {
+			return name
+		}
+		return jsonString(v["url"])
+	case []any:
+		texts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := jsonString(item); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		return strings.Join(texts, ", ")
+	}
+
+	return ""
+}
+
+// microdataProperties returns itemprop values of the item scope.
+// A nested item is represented by its name, e.g. the name of an author Person.
+func microdataProperties(scope *Tag) map[string]string {
+	props := map[string]string{}
+	add := func(name, value string) {
+		if value = strings.TrimSpace(value); value == "" {
+			return
+		}
+		if props[name] != "" {
+			value = props[name] + ", " + value
+		}
+		props[name] = value
+	}
+
+	scope.Walk(func(tag *Tag) bool {
+		if tag == scope || !tag.IsElement() {
+			return true
+		}
+
+		name := tag.Attributes["itemprop"]
+		_, nested := tag.Attributes["itemscope"]
+		switch {
+		case nested && name != "":
+			add(name, microdataProperties(tag)["name"])
+			return false
+		case nested:
+			return false
+		case name != "":
+			add(name, microdataValue(tag))
+		}
+
+		return true
+	})
+
+	return props
+}
+
+func microdataValue(tag *Tag) string {
+	switch tag.Name {
+	case "meta":
+		return tag.Attributes["content"]
+	case "time":
+		if datetime := tag.Attributes["datetime"]; datetime != "" {
+			return datetime
+		}
+	case "a", "link":
+		return tag.Attributes["href"]
+	case "img":
+		return tag.Attributes["src"]
+	}
+
+	return tag.Text()
+}
+
+// schemaType returns the type name of a schema.org URL, e.g. Article for https://schema.org/Article.
+func schemaType(itemType string) string {
+	types := strings.Fields(itemType)
+	if len(types) == 0 {
+		return ""
+	}
+	itemType = strings.TrimRight(types[0], "/")
+
+	return itemType[strings.LastIndex(itemType, "/")+1:]
+}
+
+// articleOrder sorts articles before other entities.
+func articleOrder(entity map[string]any) int {
+	schemaType := jsonString(entity["@type"])
+	if strings.Contains(schemaType, "Article") || strings.Contains(schemaType, "Posting") {
+		return 0
+	}
+
+	return 1
+}
+
+// normalizeDate returns the date in RFC 3339, the date is returned as is when it can't be parsed.
+func normalizeDate(date string) string {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t.Format(time.RFC3339)
+		}
+	}
+
+	return date
+}
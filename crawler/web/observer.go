@@ -0,0 +1,229 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reasons of skipping a link reported by Observer.OnSkipped.
+const (
+	SkipReasonIgnoredByProfile = "ignored by profile"
+	SkipReasonPageBudget       = "page budget exceeded"
+	SkipReasonBaseFailed       = "base page failed"
+	SkipReasonCanceled         = "crawl canceled"
+)
+
+// Observer is notified about the crawl progress.
+//
+// Methods are called concurrently from all crawler workers, so implementations must be safe for concurrent use.
+type Observer interface {
+	// OnQueued is called when the link is scheduled to be fetched, depth is a number of links from the base page.
+	OnQueued(url string, depth int)
+	// OnFetchStart is called before the page is requested.
+	OnFetchStart(url string)
+	// OnFetched is called when the page is downloaded.
+	OnFetched(url string, status int, bytes int64, duration time.Duration)
+	// OnError is called when the page can't be fetched or parsed.
+	OnError(url string, err error)
+	// OnSkipped is called when the link isn't fetched.
+	OnSkipped(url string, reason string)
+	// OnDone is called when the crawl started from baseURL is finished.
+	OnDone(baseURL string, pages int, duration time.Duration)
+}
+
+// NopObserver ignores all events. It may be embedded to implement only some of Observer methods.
+type NopObserver struct{}
+
+func (NopObserver) OnQueued(string, int)                        {}
+func (NopObserver) OnFetchStart(string)                         {}
+func (NopObserver) OnFetched(string, int, int64, time.Duration) {}
+func (NopObserver) OnError(string, error)                       {}
+func (NopObserver) OnSkipped(string, string)                    {}
+func (NopObserver) OnDone(string, int, time.Duration)           {}
+
+// trapObserver is the default Observer of a Crawler, it logs only links quarantined by TrapRules with Warn level,
+// so links skipped as crawler traps are never dropped silently.
+type trapObserver struct {
+	NopObserver
+	logger *slog.Logger
+}
+
+func (o trapObserver) OnSkipped(url string, reason string) {
+	if strings.HasPrefix(reason, SkipReasonTrap) {
+		o.logger.Warn("link quarantined", slog.String("url", url), slog.String("reason", reason))
+	}
+}
+
+// MultiObserver notifies all observers in the given order.
+type MultiObserver []Observer
+
+func (m MultiObserver) OnQueued(url string, depth int) {
+	for _, o := range m {
+		o.OnQueued(url, depth)
+	}
+}
+
+func (m MultiObserver) OnFetchStart(url string) {
+	for _, o := range m {
+		o.OnFetchStart(url)
+	}
+}
+
+func (m MultiObserver) OnFetched(url string, status int, bytes int64, duration time.Duration) {
+	for _, o := range m {
+		o.OnFetched(url, status, bytes, duration)
+	}
+}
+
+func (m MultiObserver) OnError(url string, err error) {
+	for _, o := range m {
+		o.OnError(url, err)
+	}
+}
+
+func (m MultiObserver) OnSkipped(url string, reason string) {
+	for _, o := range m {
+		o.OnSkipped(url, reason)
+	}
+}
+
+func (m MultiObserver) OnDone(baseURL string, pages int, duration time.Duration) {
+	for _, o := range m {
+		o.OnDone(baseURL, pages, duration)
+	}
+}
+
+// SlogObserver logs crawl events with log/slog.
+// Queued links and fetch starts are logged with Debug level, errors with Warn level and the rest with Info level.
+type SlogObserver struct {
+	logger *slog.Logger
+}
+
+func NewSlogObserver(logger *slog.Logger) *SlogObserver {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SlogObserver{logger: logger}
+}
+
+func (o *SlogObserver) OnQueued(url string, depth int) {
+	o.logger.Debug("page queued", slog.String("url", url), slog.Int("depth", depth))
+}
+
+func (o *SlogObserver) OnFetchStart(url string) {
+	o.logger.Debug("fetching page", slog.String("url", url))
+}
+
+func (o *SlogObserver) OnFetched(url string, status int, bytes int64, duration time.Duration) {
+	o.logger.Info("page fetched",
+		slog.String("url", url),
+		slog.Int("status", status),
+		slog.Int64("bytes", bytes),
+		slog.Duration("duration", duration),
+	)
+}
+
+func (o *SlogObserver) OnError(url string, err error) {
+	o.logger.Warn("page failed", slog.String("url", url), slog.String("error", err.Error()))
+}
+
+func (o *SlogObserver) OnSkipped(url string, reason string) {
+	o.logger.Info("page skipped", slog.String("url", url), slog.String("reason", reason))
+}
+
+func (o *SlogObserver) OnDone(baseURL string, pages int, duration time.Duration) {
+	o.logger.Info("crawl finished",
+		slog.String("url", baseURL),
+		slog.Int("pages", pages),
+		slog.Duration("duration", duration),
+	)
+}
+
+// Progress is a summary of a crawl.
+type Progress struct {
+	Queued  int
+	Fetched int
+	Failed  int
+	Skipped int
+	Bytes   int64
+	// Statuses are counts of fetched pages by the response status code.
+	Statuses map[int]int
+	// SkipReasons are counts of skipped links by the reason.
+	SkipReasons map[string]int
+}
+
+// ProgressObserver counts crawl events and writes the summary when a crawl is done.
+type ProgressObserver struct {
+	mutex    *sync.Mutex
+	progress Progress
+	w        io.Writer
+}
+
+// NewProgressObserver creates ProgressObserver, summary is written to w, nil w disables writing.
+func NewProgressObserver(w io.Writer) *ProgressObserver {
+	return &ProgressObserver{
+		mutex: &sync.Mutex{},
+		progress: Progress{
+			Statuses:    map[int]int{},
+			SkipReasons: map[string]int{},
+		},
+		w: w,
+	}
+}
+
+// Progress returns a snapshot of the crawl summary.
+func (o *ProgressObserver) Progress() Progress {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	p := o.progress
+	p.Statuses = maps.Clone(o.progress.Statuses)
+	p.SkipReasons = maps.Clone(o.progress.SkipReasons)
+
+	return p
+}
+
+func (o *ProgressObserver) OnQueued(string, int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.progress.Queued++
+}
+
+func (o *ProgressObserver) OnFetchStart(string) {}
+
+func (o *ProgressObserver) OnFetched(_ string, status int, bytes int64, _ time.Duration) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.progress.Fetched++
+	o.progress.Bytes += bytes
+	o.progress.Statuses[status]++
+}
+
+func (o *ProgressObserver) OnError(string, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.progress.Failed++
+}
+
+func (o *ProgressObserver) OnSkipped(_ string, reason string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.progress.Skipped++
+	o.progress.SkipReasons[reason]++
+}
+
+func (o *ProgressObserver) OnDone(baseURL string, pages int, duration time.Duration) {
+	if o.w == nil {
+		return
+	}
+
+	p := o.Progress()
+	_, _ = fmt.Fprintf(o.w, "crawled %s: %d pages in %s, queued %d, fetched %d (%d bytes), failed %d, skipped %d\n",
+		baseURL, pages, duration.Round(time.Millisecond), p.Queued, p.Fetched, p.Bytes, p.Failed, p.Skipped)
+}
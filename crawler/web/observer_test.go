@@ -0,0 +1,127 @@
+package web_test
+
+import (
+	"bytes"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type eventsObserver struct {
+	web.NopObserver
+
+	mutex  sync.Mutex
+	events []string
+}
+
+func (o *eventsObserver) add(event string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.events = append(o.events, event)
+}
+
+func (o *eventsObserver) OnQueued(url string, _ int) {
+	o.add("queued " + url)
+}
+
+func (o *eventsObserver) OnFetched(url string, _ int, _ int64, _ time.Duration) {
+	o.add("fetched " + url)
+}
+
+func (o *eventsObserver) OnError(url string, _ error) {
+	o.add("error " + url)
+}
+
+func (o *eventsObserver) OnSkipped(url string, reason string) {
+	o.add("skipped " + url + ": " + reason)
+}
+
+func (o *eventsObserver) OnDone(baseURL string, pages int, _ time.Duration) {
+	o.add("done " + baseURL)
+}
+
+func TestCrawler_ScrapeObserver(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`<html><body>
+			<a href="/tour">Tour</a>
+			<a href="/missing">Missing</a>
+			<a href="/logout">Logout</a>
+		</body></html>`))
+	})
+	mux.HandleFunc("/tour", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><p>Welcome!</p></body></html>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	profiles, err := web.NewProfiles(web.Profile{Hosts: []string{"*"}, IgnoreLinks: []string{"/logout$"}})
+	require.NoError(t, err)
+
+	events := &eventsObserver{}
+	progress := web.NewProgressObserver(nil)
+	logs := &bytes.Buffer{}
+	s := web.NewCrawler(
+		web.WithProfiles(profiles),
+		web.WithObserver(web.MultiObserver{
+			events,
+			progress,
+			web.NewSlogObserver(slog.New(slog.NewTextHandler(logs, nil))),
+		}),
+	)
+
+	// when
+	_, err = s.ScrapePages(server.URL + "/")
+
+	// expected
+	require.NoError(t, err)
+
+	sort.Strings(events.events)
+	assert.Equal(t, []string{
+		"done " + server.URL + "/",
+		"error " + server.URL + "/missing",
+		"fetched " + server.URL + "/",
+		"fetched " + server.URL + "/missing",
+		"fetched " + server.URL + "/tour",
+		"queued " + server.URL + "/",
+		"queued " + server.URL + "/missing",
+		"queued " + server.URL + "/tour",
+		"skipped " + server.URL + "/logout: " + web.SkipReasonIgnoredByProfile,
+	}, events.events)
+
+	p := progress.Progress()
+	assert.Equal(t, 3, p.Queued)
+	assert.Equal(t, 3, p.Fetched)
+	assert.Equal(t, 1, p.Failed)
+	assert.Equal(t, 1, p.Skipped)
+	assert.Equal(t, map[int]int{http.StatusOK: 2, http.StatusNotFound: 1}, p.Statuses)
+	assert.Positive(t, p.Bytes)
+
+	assert.Contains(t, logs.String(), "msg=\"page fetched\"")
+	assert.Contains(t, logs.String(), "msg=\"crawl finished\"")
+	assert.Equal(t, 1, strings.Count(logs.String(), "level=WARN"))
+}
+
+func TestProgressObserver_OnDone(t *testing.T) {
+	w := &bytes.Buffer{}
+	o := web.NewProgressObserver(w)
+
+	o.OnQueued("https://go.dev/", 0)
+	o.OnFetched("https://go.dev/", http.StatusOK, 1024, time.Second)
+	o.OnDone("https://go.dev/", 1, 1500*time.Millisecond)
+
+	assert.Equal(t, "crawled https://go.dev/: 1 pages in 1.5s, queued 1, fetched 1 (1024 bytes), failed 0, skipped 0\n",
+		w.String())
+}
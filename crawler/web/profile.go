@@ -0,0 +1,208 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidProfile = errors.New("invalid extraction profile")
+
+// Profile describes how pages of a particular site are extracted.
+//
+// Profile is matched by Hosts and URLPattern. When both are set, both must match.
+type Profile struct {
+	Name string `json:"name"`
+	// Hosts are page hosts the Profile is used for.
+	// "*.example.com" matches any subdomain of example.com, "*" matches any host.
+	Hosts []string `json:"hosts"`
+	// URLPattern is a regular expression matched against the whole page URL.
+	URLPattern string `json:"url_pattern"`
+	// Include are CSS selectors of page parts the content is taken from.
+	// When empty, the crawler ExtractionMode defines the content.
+	Include []string `json:"include"`
+	// Exclude are CSS selectors of page parts which are dropped from the content.
+	Exclude []string `json:"exclude"`
+	// Title is a CSS selector of the page title.
+	Title string `json:"title"`
+	// Fields are CSS selectors of additional named page parts, e.g. {"author": ".byline"}.
+	Fields map[string]string `json:"fields"`
+	// IgnoreLinks are regular expressions of links which are not followed from the page.
+	IgnoreLinks []string `json:"ignore_links"`
+}
+
+// Profiles is a compiled list of extraction profiles.
+type Profiles struct {
+	profiles []compiledProfile
+}
+
+type compiledProfile struct {
+	Profile
+
+	urlPattern  *regexp.Regexp
+	include     Selector
+	exclude     Selector
+	title       Selector
+	fields      map[string]Selector
+	ignoreLinks []*regexp.Regexp
+}
+
+// NewProfiles compiles selectors and patterns of profiles.
+// Profiles are matched in the given order, the first matched one is used.
+func NewProfiles(profiles ...Profile) (*Profiles, error) {
+	result := &Profiles{profiles: make([]compiledProfile, 0, len(profiles))}
+	for _, p := range profiles {
+		compiled, err := compileProfile(p)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidProfile, p.Name, err)
+		}
+		result.profiles = append(result.profiles, compiled)
+	}
+
+	return result, nil
+}
+
+// LoadProfiles reads profiles from a JSON file with an array of Profile objects.
+func LoadProfiles(path string) (*Profiles, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open profiles file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return ParseProfiles(f)
+}
+
+// ParseProfiles reads profiles in the LoadProfiles format.
+func ParseProfiles(r io.Reader) (*Profiles, error) {
+	var profiles []Profile
+	if err := json.NewDecoder(r).Decode(&profiles); err != nil {
+		return nil, fmt.Errorf("cannot decode profiles: %w", err)
+	}
+
+	return NewProfiles(profiles...)
+}
+
+// Match returns the first Profile matched by the page URL.
+func (p *Profiles) Match(pageURL string) (Profile, bool) {
+	if compiled := p.match(pageURL); compiled != nil {
+		return compiled.Profile, true
+	}
+
+	return Profile{}, false
+}
+
+func (p *Profiles) match(pageURL string) *compiledProfile {
+	if p == nil {
+		return nil
+	}
+
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	for i := range p.profiles {
+		if p.profiles[i].match(u) {
+			return &p.profiles[i]
+		}
+	}
+
+	return nil
+}
+
+func compileProfile(p Profile) (compiledProfile, error) {
+	if len(p.Hosts) == 0 && p.URLPattern == "" {
+		return compiledProfile{}, errors.New("either hosts or url pattern must be set")
+	}
+
+	compiled := compiledProfile{Profile: p, fields: map[string]Selector{}}
+	var err error
+	if p.URLPattern != "" {
+		if compiled.urlPattern, err = regexp.Compile(p.URLPattern); err != nil {
+			return compiledProfile{}, fmt.Errorf("url pattern: %w", err)
+		}
+	}
+	if len(p.Include) != 0 {
+		if compiled.include, err = CompileSelector(strings.Join(p.Include, ", ")); err != nil {
+			return compiledProfile{}, err
+		}
+	}
+	if len(p.Exclude) != 0 {
+		if compiled.exclude, err = CompileSelector(strings.Join(p.Exclude, ", ")); err != nil {
+			return compiledProfile{}, err
+		}
+	}
+	if p.Title != "" {
+		if compiled.title, err = CompileSelector(p.Title); err != nil {
+			return compiledProfile{}, err
+		}
+	}
+	for name, selector := range p.Fields {
+		if compiled.fields[name], err = CompileSelector(selector); err != nil {
+			return compiledProfile{}, err
+		}
+	}
+	for _, pattern := range p.IgnoreLinks {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return compiledProfile{}, fmt.Errorf("ignore links: %w", err)
+		}
+		compiled.ignoreLinks = append(compiled.ignoreLinks, re)
+	}
+
+	return compiled, nil
+}
+
+func (p *compiledProfile) match(u *url.URL) bool {
+	if len(p.Hosts) != 0 && !p.matchHost(u.Hostname()) {
+		return false
+	}
+
+	return p.urlPattern == nil || p.urlPattern.MatchString(u.String())
+}
+
+func (p *compiledProfile) matchHost(host string) bool {
+	for _, h := range p.Hosts {
+		switch {
+		case h == "*", strings.EqualFold(h, host):
+			return true
+		case strings.HasPrefix(h, "*."):
+			if strings.HasSuffix(strings.ToLower(host), strings.ToLower(h[1:])) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// excluded returns tags dropped from the content by the Exclude selectors.
+func (p *compiledProfile) excluded(root *Tag) map[*Tag]bool {
+	excluded := map[*Tag]bool{}
+	if p == nil || len(p.Exclude) == 0 {
+		return excluded
+	}
+
+	for _, tag := range root.Select(p.exclude) {
+		excluded[tag] = true
+	}
+
+	return excluded
+}
+
+// ignoreLink reports whether the link must not be followed.
+func (p *compiledProfile) ignoreLink(link string) bool {
+	for _, re := range p.ignoreLinks {
+		if re.MatchString(link) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+package web_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	profiles, err := web.LoadProfiles("testdata/profiles.json")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		url     string
+		profile string
+	}{
+		{url: "https://go.dev/learn/", profile: "go.dev"},
+		{url: "https://pkg.go.dev/net/http", profile: "go.dev"},
+		{url: "https://example.com/wiki/Main_Page", profile: "wiki"},
+		{url: "https://example.com/blog/", profile: ""},
+		{url: "https://notgo.dev/", profile: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			profile, ok := profiles.Match(tc.url)
+
+			assert.Equal(t, tc.profile != "", ok)
+			assert.Equal(t, tc.profile, profile.Name)
+		})
+	}
+}
+
+func TestParseProfiles_Invalid(t *testing.T) {
+	testCases := map[string]string{
+		"should fail without hosts and url pattern": `[{"name": "empty", "include": ["p"]}]`,
+		"should fail with invalid selector":         `[{"name": "bad", "hosts": ["*"], "include": ["p >"]}]`,
+		"should fail with invalid url pattern":      `[{"name": "bad", "url_pattern": "("}]`,
+	}
+
+	for name, config := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := web.ParseProfiles(strings.NewReader(config))
+
+			assert.ErrorIs(t, err, web.ErrInvalidProfile)
+		})
+	}
+}
+
+func TestCrawler_ScrapePagesWithProfiles(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body>
+			<a href="/wiki/Generics">Generics</a>
+			<a href="/wiki/Special:Login">Login</a>
+		</body></html>`))
+	})
+	mux.HandleFunc("/wiki/Generics", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body>
+			<div class="menu">Menu</div>
+			<h1 class="wiki-title">Generics</h1>
+			<span class="wiki-author">Gopher</span>
+			<div class="wiki-body">
+				<p>Generics let functions work with any of a set of types.</p>
+				<div class="edit-link">Edit this page</div>
+			</div>
+			<ul class="wiki-tags"><li>go</li><li>types</li></ul>
+		</body></html>`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	profiles, err := web.NewProfiles(
+		web.Profile{
+			Name:        "home",
+			URLPattern:  "/$",
+			IgnoreLinks: []string{"/wiki/Special:"},
+		},
+		web.Profile{
+			Name:       "wiki",
+			URLPattern: "/wiki/",
+			Include:    []string{".wiki-body"},
+			Exclude:    []string{".edit-link"},
+			Title:      ".wiki-title",
+			Fields: map[string]string{
+				"author": ".wiki-author",
+				"tags":   ".wiki-tags li",
+			},
+		},
+	)
+	require.NoError(t, err)
+	s := web.NewCrawler(web.WithProfiles(profiles))
+
+	// when
+	pages, err := s.ScrapePages(server.URL + "/")
+
+	// expected
+	require.NoError(t, err)
+	assert.Len(t, pages, 2)
+	assert.NotContains(t, pages, server.URL+"/wiki/Special:Login")
+
+	page := pages[server.URL+"/wiki/Generics"]
+	assert.Equal(t, "Generics", page.Title)
+	assert.Equal(t, []string{"Generics let functions work with any of a set of types."}, page.Content)
+	assert.Equal(t, []string{"Gopher"}, page.Fields["author"])
+	assert.Equal(t, []string{"go", "types"}, page.Fields["tags"])
+}
@@ -0,0 +1,285 @@
+package web
+
+import (
+	"container/heap"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMinRecrawlInterval = time.Hour
+	DefaultMaxRecrawlInterval = 30 * 24 * time.Hour
+)
+
+// RecrawlState is the visit history of a URL.
+type RecrawlState struct {
+	URL string
+	// Hash is the hash of the page title and content on the last visit.
+	Hash string
+	// Visits is a number of successful visits.
+	Visits int
+	// Changes is a number of visits on which the content hash differed from the previous one.
+	Changes int
+	// Interval is the estimated time between content changes.
+	Interval  time.Duration
+	LastVisit time.Time
+	NextVisit time.Time
+}
+
+// RecrawlHandler is called for every revisited page, changed reports whether its content hash changed.
+type RecrawlHandler func(page Page, changed bool)
+
+// RecrawlScheduler revisits crawled pages with intervals adapted to how often they change.
+//
+// The interval of a URL starts from the minimal one, it's doubled every time the content is unchanged
+// and halved every time it's changed, staying between the minimal and the maximal interval.
+type RecrawlScheduler struct {
+	crawler *Crawler
+	handler RecrawlHandler
+
+	mutex   *sync.Mutex
+	states  map[string]*recrawlEntry
+	queue   *recrawlQueue
+	wake    chan struct{}
+	minimum time.Duration
+	maximum time.Duration
+}
+
+// RecrawlOption configures a RecrawlScheduler.
+type RecrawlOption func(s *RecrawlScheduler)
+
+// WithRecrawlIntervals sets bounds of revisit intervals.
+func WithRecrawlIntervals(minimum, maximum time.Duration) RecrawlOption {
+	return func(s *RecrawlScheduler) {
+		s.minimum, s.maximum = minimum, max(minimum, maximum)
+	}
+}
+
+// WithRecrawlHandler sets the handler of revisited pages, e.g. to index changed pages again.
+func WithRecrawlHandler(handler RecrawlHandler) RecrawlOption {
+	return func(s *RecrawlScheduler) {
+		s.handler = handler
+	}
+}
+
+// NewRecrawlScheduler creates a scheduler which revisits pages with the crawler.
+func NewRecrawlScheduler(crawler *Crawler, options ...RecrawlOption) *RecrawlScheduler {
+	s := &RecrawlScheduler{
+		crawler: crawler,
+		handler: func(Page, bool) {},
+		mutex:   &sync.Mutex{},
+		states:  map[string]*recrawlEntry{},
+		queue:   &recrawlQueue{},
+		wake:    make(chan struct{}, 1),
+		minimum: DefaultMinRecrawlInterval,
+		maximum: DefaultMaxRecrawlInterval,
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// AddPages records the visit of pages of a crawl, e.g. returned by Crawler.ScrapePages.
+func (s *RecrawlScheduler) AddPages(pages map[string]Page) {
+	now := time.Now()
+	for _, page := range pages {
+		s.Visit(page, now)
+	}
+}
+
+// Visit records the visit of the page at the given time and schedules the next one.
+func (s *RecrawlScheduler) Visit(page Page, at time.Time) RecrawlState {
+	state, _ := s.visit(page, at)
+
+	return state
+}
+
+// visit is like Visit but also reports whether the content changed since the previous visit.
+func (s *RecrawlScheduler) visit(page Page, at time.Time) (RecrawlState, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	hash := contentHash(page)
+	entry, ok := s.states[page.URL]
+	changed := ok && entry.state.Hash != hash
+	switch {
+	case !ok:
+		entry = &recrawlEntry{index: -1, state: RecrawlState{URL: page.URL, Interval: s.minimum}}
+		s.states[page.URL] = entry
+	case changed:
+		entry.state.Changes++
+		entry.state.Interval = max(entry.state.Interval/2, s.minimum)
+	default:
+		entry.state.Interval = min(entry.state.Interval*2, s.maximum)
+	}
+
+	entry.state.Hash = hash
+	entry.state.Visits++
+	entry.state.LastVisit = at
+	s.schedule(entry, at.Add(entry.state.Interval))
+
+	return entry.state, changed
+}
+
+// State returns the visit history of the URL.
+func (s *RecrawlScheduler) State(url string) (RecrawlState, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, ok := s.states[url]
+	if !ok {
+		return RecrawlState{}, false
+	}
+
+	return entry.state, true
+}
+
+// Run revisits due pages until ctx is canceled.
+// Pages are fetched by Crawler.FetchPage with the crawler number of workers, failed pages are retried
+// after their current interval.
+func (s *RecrawlScheduler) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		case <-s.wake:
+		}
+
+		s.revisit(s.due(time.Now()))
+
+		timer.Stop()
+		if next, ok := s.next(); ok {
+			timer.Reset(time.Until(next))
+		}
+	}
+}
+
+// revisit fetches links concurrently and records visits.
+func (s *RecrawlScheduler) revisit(links []string) {
+	semaphore := make(chan struct{}, s.crawler.workers)
+	wg := &sync.WaitGroup{}
+	for _, link := range links {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			page, _, err := s.crawler.FetchPage(link)
+			if err != nil {
+				s.retry(link, time.Now())
+				return
+			}
+
+			_, changed := s.visit(page, time.Now())
+			s.handler(page, changed)
+		}()
+	}
+	wg.Wait()
+}
+
+// retry schedules the failed link after its current interval.
+func (s *RecrawlScheduler) retry(link string, at time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry := s.states[link]
+	s.schedule(entry, at.Add(entry.state.Interval))
+}
+
+// due removes links which must be visited at the moment from the queue.
+func (s *RecrawlScheduler) due(now time.Time) []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	links := make([]string, 0)
+	for s.queue.Len() != 0 && !(*s.queue)[0].state.NextVisit.After(now) {
+		entry, _ := heap.Pop(s.queue).(*recrawlEntry)
+		links = append(links, entry.state.URL)
+	}
+
+	return links
+}
+
+// next returns the time of the closest visit, false is returned when nothing is scheduled.
+func (s *RecrawlScheduler) next() (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.queue.Len() == 0 {
+		return time.Time{}, false
+	}
+
+	return (*s.queue)[0].state.NextVisit, true
+}
+
+// schedule sets the next visit of the entry and wakes Run up, so it doesn't sleep past the visit.
+func (s *RecrawlScheduler) schedule(entry *recrawlEntry, at time.Time) {
+	entry.state.NextVisit = at
+	if entry.index < 0 {
+		heap.Push(s.queue, entry)
+	} else {
+		heap.Fix(s.queue, entry.index)
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// contentHash returns the hash of the extracted page text, so changes of scripts or markup are ignored.
+func contentHash(page Page) string {
+	h := sha256.New()
+	h.Write([]byte(page.Title))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.Join(page.Content, "\n")))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type recrawlEntry struct {
+	state RecrawlState
+	// index is the position in recrawlQueue, -1 when the entry isn't queued.
+	index int
+}
+
+// recrawlQueue implements heap.Interface with the earliest visit on top.
+type recrawlQueue []*recrawlEntry
+
+func (q recrawlQueue) Len() int { return len(q) }
+
+func (q recrawlQueue) Less(i, j int) bool { return q[i].state.NextVisit.Before(q[j].state.NextVisit) }
+
+func (q recrawlQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *recrawlQueue) Push(x any) {
+	entry, _ := x.(*recrawlEntry)
+	entry.index = len(*q)
+	*q = append(*q, entry)
+}
+
+func (q *recrawlQueue) Pop() any {
+	old := *q
+	entry := old[len(old)-1]
+	entry.index = -1
+	*q = old[:len(old)-1]
+
+	return entry
+}
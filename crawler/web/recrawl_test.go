@@ -0,0 +1,134 @@
+package web_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/webtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecrawlScheduler_Visit(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	page := func(content string) web.Page {
+		return web.Page{URL: "https://example.com/", Content: []string{content}}
+	}
+
+	testCases := []struct {
+		name     string
+		contents []string
+		interval time.Duration
+		changes  int
+	}{
+		{
+			name:     "should start from the minimal interval",
+			contents: []string{"a"},
+			interval: time.Hour,
+		},
+		{
+			name:     "should double the interval while the content is unchanged",
+			contents: []string{"a", "a", "a"},
+			interval: 4 * time.Hour,
+		},
+		{
+			name:     "should not exceed the maximal interval",
+			contents: []string{"a", "a", "a", "a", "a", "a"},
+			interval: 16 * time.Hour,
+		},
+		{
+			name:     "should halve the interval when the content is changed",
+			contents: []string{"a", "a", "a", "b"},
+			interval: 2 * time.Hour,
+			changes:  1,
+		},
+		{
+			name:     "should not go below the minimal interval",
+			contents: []string{"a", "b", "c"},
+			interval: time.Hour,
+			changes:  2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			s := web.NewRecrawlScheduler(web.NewCrawler(), web.WithRecrawlIntervals(time.Hour, 16*time.Hour))
+
+			// when
+			at := start
+			var state web.RecrawlState
+			for _, content := range tc.contents {
+				state = s.Visit(page(content), at)
+				at = state.NextVisit
+			}
+
+			// expected
+			assert.Equal(t, tc.interval, state.Interval)
+			assert.Equal(t, tc.changes, state.Changes)
+			assert.Equal(t, len(tc.contents), state.Visits)
+			assert.Equal(t, state.LastVisit.Add(tc.interval), state.NextVisit)
+
+			stored, ok := s.State("https://example.com/")
+			require.True(t, ok)
+			assert.Equal(t, state, stored)
+		})
+	}
+}
+
+func TestRecrawlScheduler_Run(t *testing.T) {
+	// given
+	server := webtest.NewServer(t, webtest.Site{Pages: map[string]webtest.Page{
+		"/news":  {Text: []string{"news 0"}},
+		"/about": {Text: []string{"about"}},
+	}})
+
+	c := web.NewCrawler()
+	pages, err := c.ScrapePages(server.Link("/about"))
+	require.NoError(t, err)
+	news, _, err := c.FetchPage(server.Link("/news"))
+	require.NoError(t, err)
+	pages[news.URL] = news
+
+	mutex := sync.Mutex{}
+	changed := map[string]int{}
+	visits := 0
+	s := web.NewRecrawlScheduler(c,
+		web.WithRecrawlIntervals(10*time.Millisecond, 80*time.Millisecond),
+		web.WithRecrawlHandler(func(page web.Page, ok bool) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if ok {
+				changed[page.URL]++
+			}
+			// the news page is changed after every visit.
+			if page.URL == server.Link("/news") {
+				visits++
+				server.SetPage(t, "/news", webtest.Page{Text: []string{fmt.Sprintf("news %d", visits)}})
+			}
+		}),
+	)
+	s.AddPages(pages)
+
+	// when
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	err = s.Run(ctx)
+
+	// expected
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	newsState, _ := s.State(server.Link("/news"))
+	aboutState, _ := s.State(server.Link("/about"))
+	assert.Equal(t, 10*time.Millisecond, newsState.Interval)
+	assert.Equal(t, 80*time.Millisecond, aboutState.Interval)
+	assert.Greater(t, newsState.Visits, aboutState.Visits, "changing pages must be visited more often")
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, newsState.Changes, changed[server.Link("/news")])
+	assert.Zero(t, changed[server.Link("/about")])
+}
@@ -0,0 +1,355 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidSelector = errors.New("invalid selector")
+
+// Selector is a compiled CSS selector.
+//
+// Supported syntax:
+//   - type and universal selectors: p, *;
+//   - class and id selectors: .content, #main;
+//   - attribute selectors: [href], [lang=en], [class~=a], [href^=https], [src$=".png"], [title*=go], [lang|=en];
+//   - descendant and child combinators: article p, ul > li;
+//   - selector lists: h1, h2.
+type Selector struct {
+	raw   string
+	group []complexSelector
+}
+
+// complexSelector is a chain of compound selectors joined by combinators, e.g. article > .content p.
+type complexSelector struct {
+	compounds []compoundSelector
+	// combinators[i] joins compounds[i-1] and compounds[i], combinators[0] is unused.
+	combinators []combinator
+}
+
+type combinator byte
+
+const (
+	descendantCombinator combinator = ' '
+	childCombinator      combinator = '>'
+)
+
+// compoundSelector is a sequence of simple selectors without combinators, e.g. a.external[href].
+type compoundSelector struct {
+	name       string
+	id         string
+	classes    []string
+	attributes []attributeSelector
+}
+
+type attributeSelector struct {
+	name     string
+	operator string
+	value    string
+}
+
+// CompileSelector parses a CSS selector.
+func CompileSelector(selector string) (Selector, error) {
+	p := selectorParser{input: selector}
+	group, err := p.parseGroup()
+	if err != nil {
+		return Selector{}, fmt.Errorf("%w %q: %w", ErrInvalidSelector, selector, err)
+	}
+
+	return Selector{raw: selector, group: group}, nil
+}
+
+// MustCompileSelector is like CompileSelector but panics if the selector can't be parsed.
+func MustCompileSelector(selector string) Selector {
+	s, err := CompileSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+// String returns the source text of the Selector.
+func (s Selector) String() string {
+	return s.raw
+}
+
+// Match reports whether the Tag is matched by any selector in the group.
+func (s Selector) Match(tag *Tag) bool {
+	for _, c := range s.group {
+		if c.match(tag, len(c.compounds)-1) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match checks compounds from the right to the left, starting from the compound under index i.
+func (c complexSelector) match(tag *Tag, i int) bool {
+	if !c.compounds[i].match(tag) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	switch c.combinators[i] {
+	case childCombinator:
+		return tag.Parent != nil && c.match(tag.Parent, i-1)
+	case descendantCombinator:
+		for parent := tag.Parent; parent != nil; parent = parent.Parent {
+			if c.match(parent, i-1) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c compoundSelector) match(tag *Tag) bool {
+	if !tag.IsElement() {
+		return false
+	}
+	if c.name != "" && c.name != "*" && c.name != tag.Name {
+		return false
+	}
+	if c.id != "" && c.id != tag.ID() {
+		return false
+	}
+	for _, class := range c.classes {
+		if !tag.HasClass(class) {
+			return false
+		}
+	}
+	for _, a := range c.attributes {
+		if !a.match(tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a attributeSelector) match(tag *Tag) bool {
+	value, ok := tag.Attributes[a.name]
+	if !ok {
+		return false
+	}
+
+	switch a.operator {
+	case "":
+		return true
+	case "=":
+		return value == a.value
+	case "~=":
+		for _, word := range strings.Fields(value) {
+			if word == a.value {
+				return true
+			}
+		}
+		return false
+	case "^=":
+		return a.value != "" && strings.HasPrefix(value, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(value, a.value)
+	case "*=":
+		return a.value != "" && strings.Contains(value, a.value)
+	case "|=":
+		return value == a.value || strings.HasPrefix(value, a.value+"-")
+	}
+
+	return false
+}
+
+type selectorParser struct {
+	input string
+	pos   int
+}
+
+func (p *selectorParser) parseGroup() ([]complexSelector, error) {
+	group := make([]complexSelector, 0)
+	for {
+		c, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, c)
+
+		p.skipSpaces()
+		if p.eof() {
+			return group, nil
+		}
+		if p.input[p.pos] != ',' {
+			return nil, fmt.Errorf("unexpected %q at %d", p.input[p.pos], p.pos)
+		}
+		p.pos++
+	}
+}
+
+func (p *selectorParser) parseComplex() (complexSelector, error) {
+	c := complexSelector{}
+	p.skipSpaces()
+
+	comb := descendantCombinator
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return complexSelector{}, err
+		}
+		c.compounds = append(c.compounds, compound)
+		c.combinators = append(c.combinators, comb)
+
+		hasSpace := p.skipSpaces()
+		if p.eof() || p.input[p.pos] == ',' {
+			return c, nil
+		}
+
+		switch {
+		case p.input[p.pos] == '>':
+			comb = childCombinator
+			p.pos++
+			p.skipSpaces()
+		case hasSpace:
+			comb = descendantCombinator
+		default:
+			return complexSelector{}, fmt.Errorf("unexpected %q at %d", p.input[p.pos], p.pos)
+		}
+	}
+}
+
+func (p *selectorParser) parseCompound() (compoundSelector, error) {
+	c := compoundSelector{}
+	start := p.pos
+
+	if !p.eof() && p.input[p.pos] == '*' {
+		c.name = "*"
+		p.pos++
+	} else if name := p.parseIdent(); name != "" {
+		c.name = strings.ToLower(name)
+	}
+
+	for !p.eof() {
+		switch p.input[p.pos] {
+		case '.':
+			p.pos++
+			class := p.parseIdent()
+			if class == "" {
+				return compoundSelector{}, fmt.Errorf("expected class name at %d", p.pos)
+			}
+			c.classes = append(c.classes, class)
+		case '#':
+			p.pos++
+			id := p.parseIdent()
+			if id == "" {
+				return compoundSelector{}, fmt.Errorf("expected id at %d", p.pos)
+			}
+			c.id = id
+		case '[':
+			p.pos++
+			a, err := p.parseAttribute()
+			if err != nil {
+				return compoundSelector{}, err
+			}
+			c.attributes = append(c.attributes, a)
+		default:
+			if p.pos == start {
+				return compoundSelector{}, fmt.Errorf("unexpected %q at %d", p.input[p.pos], p.pos)
+			}
+			return c, nil
+		}
+	}
+
+	if p.pos == start {
+		return compoundSelector{}, fmt.Errorf("expected selector at %d", p.pos)
+	}
+
+	return c, nil
+}
+
+func (p *selectorParser) parseAttribute() (attributeSelector, error) {
+	p.skipSpaces()
+	a := attributeSelector{name: strings.ToLower(p.parseIdent())}
+	if a.name == "" {
+		return attributeSelector{}, fmt.Errorf("expected attribute name at %d", p.pos)
+	}
+	p.skipSpaces()
+
+	for _, op := range []string{"=", "~=", "^=", "$=", "*=", "|="} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			a.operator = op
+			p.pos += len(op)
+			break
+		}
+	}
+
+	if a.operator != "" {
+		p.skipSpaces()
+		value, err := p.parseValue()
+		if err != nil {
+			return attributeSelector{}, err
+		}
+		a.value = value
+		p.skipSpaces()
+	}
+
+	if p.eof() || p.input[p.pos] != ']' {
+		return attributeSelector{}, fmt.Errorf("expected ']' at %d", p.pos)
+	}
+	p.pos++
+
+	return a, nil
+}
+
+func (p *selectorParser) parseValue() (string, error) {
+	if p.eof() {
+		return "", fmt.Errorf("expected attribute value at %d", p.pos)
+	}
+
+	quote := p.input[p.pos]
+	if quote != '"' && quote != '\'' {
+		value := p.parseIdent()
+		if value == "" {
+			return "", fmt.Errorf("expected attribute value at %d", p.pos)
+		}
+		return value, nil
+	}
+
+	end := strings.IndexByte(p.input[p.pos+1:], quote)
+	if end == -1 {
+		return "", fmt.Errorf("unterminated string at %d", p.pos)
+	}
+	value := p.input[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+
+	return value, nil
+}
+
+func (p *selectorParser) parseIdent() string {
+	start := p.pos
+	for !p.eof() && isIdentByte(p.input[p.pos]) {
+		p.pos++
+	}
+
+	return p.input[start:p.pos]
+}
+
+func (p *selectorParser) skipSpaces() bool {
+	start := p.pos
+	for !p.eof() && strings.IndexByte(" \t\n\r\f", p.input[p.pos]) != -1 {
+		p.pos++
+	}
+
+	return p.pos != start
+}
+
+func (p *selectorParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func isIdentByte(b byte) bool {
+	return b == '-' || b == '_' || b >= 0x80 ||
+		('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
@@ -0,0 +1,116 @@
+package web_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTag_Find(t *testing.T) {
+	c := web.NewClient(web.BaseRetryPolicy(), 5)
+
+	page := `<html>
+		<body>
+			<nav id="menu"><p>Menu</p><a href="/">Home</a></nav>
+			<article class="post featured" lang="en-US">
+				<h1>Title</h1>
+				<div class="content">
+					<p>First paragraph</p>
+					<section><p>Nested paragraph</p></section>
+					<img src="/cat.png" alt="Cat">
+				</div>
+				<a href="https://go.dev/tour" rel="external nofollow">Go tour</a>
+			</article>
+		</body>
+	</html>`
+	root, err := c.ParseTree(io.NopCloser(strings.NewReader(page)))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		selector string
+		expected []string
+	}{
+		{
+			name:     "should find tags by type",
+			selector: "p",
+			expected: []string{"Menu", "First paragraph", "Nested paragraph"},
+		},
+		{
+			name:     "should find tags by descendant combinator",
+			selector: "article .content p",
+			expected: []string{"First paragraph", "Nested paragraph"},
+		},
+		{
+			name:     "should find tags by child combinator",
+			selector: "div.content > p",
+			expected: []string{"First paragraph"},
+		},
+		{
+			name:     "should find tags by id",
+			selector: "#menu a",
+			expected: []string{"Home"},
+		},
+		{
+			name:     "should find tags by several classes",
+			selector: ".featured.post h1",
+			expected: []string{"Title"},
+		},
+		{
+			name:     "should find tags by attribute presence and value",
+			selector: `a[href^="https"][rel~=nofollow]`,
+			expected: []string{"Go tour"},
+		},
+		{
+			name:     "should find tags by attribute prefix with dash",
+			selector: "[lang|=en] > h1",
+			expected: []string{"Title"},
+		},
+		{
+			name:     "should find tags matched by any selector in a list",
+			selector: "h1, nav > a",
+			expected: []string{"Home", "Title"},
+		},
+		{
+			name:     "should find nothing when chain isn't matched",
+			selector: "nav .content p",
+			expected: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			tags, err := root.Find(tc.selector)
+
+			// expected
+			require.NoError(t, err)
+			texts := make([]string, 0, len(tags))
+			for _, tag := range tags {
+				texts = append(texts, tag.Text())
+			}
+			assert.Equal(t, tc.expected, texts)
+		})
+	}
+
+	t.Run("should find self close tags by attribute suffix", func(t *testing.T) {
+		tags, err := root.Find(`img[src$=".png"]`)
+
+		require.NoError(t, err)
+		require.Len(t, tags, 1)
+		assert.Equal(t, "Cat", tags[0].Attributes["alt"])
+	})
+}
+
+func TestCompileSelector_Invalid(t *testing.T) {
+	for _, selector := range []string{"", "p >", "> p", "a[href", "a[=x]", "p,", ".", "a[href=]", "p!"} {
+		t.Run(selector, func(t *testing.T) {
+			_, err := web.CompileSelector(selector)
+
+			assert.ErrorIs(t, err, web.ErrInvalidSelector)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package web
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// SkipReasonTrap prefixes Observer.OnSkipped reasons of links quarantined by TrapRules, e.g. "crawler trap: url too long".
+const SkipReasonTrap = "crawler trap: "
+
+// Rules of TrapRules reported in Crawler.Quarantine.
+const (
+	TrapURLTooLong        = "url too long"
+	TrapRepeatedSegments  = "repeated path segments"
+	TrapQueryCombinations = "too many query combinations"
+	TrapHostBudget        = "host page budget exceeded"
+	TrapSessionID         = "session id in url"
+)
+
+// TrapRules are heuristics of endless URL spaces like calendars, faceted navigation or session ids in URLs.
+// A zero value of a rule disables it.
+type TrapRules struct {
+	// MaxURLLength is the maximal length of a URL.
+	MaxURLLength int
+	// MaxRepeatedSegments is how many times the same segment may occur in a URL path, e.g. /a/b/a/b/a/b.
+	MaxRepeatedSegments int
+	// MaxQueryCombinations is the maximal number of different queries of the same path.
+	MaxQueryCombinations int
+	// MaxPagesPerHost is the page budget of a host in a crawl.
+	MaxPagesPerHost int
+	// SessionParams are names of query parameters holding session ids, they are matched case-insensitively.
+	SessionParams []string
+}
+
+func DefaultTrapRules() TrapRules {
+	return TrapRules{
+		MaxURLLength:         2048,
+		MaxRepeatedSegments:  3,
+		MaxQueryCombinations: 100,
+		MaxPagesPerHost:      10000,
+		SessionParams: []string{
+			"sid", "sessionid", "session_id", "jsessionid", "phpsessid", "aspsessionid", "cfid", "cftoken",
+		},
+	}
+}
+
+// Trap returns the rule tripped by the link itself, an empty string is returned for links which may be fetched.
+// MaxQueryCombinations and MaxPagesPerHost depend on other links of a crawl, so they aren't checked.
+func (r TrapRules) Trap(link string) string {
+	if r.MaxURLLength != 0 && len(link) > r.MaxURLLength {
+		return TrapURLTooLong
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	if r.hasSessionID(u) {
+		return TrapSessionID
+	}
+	if r.MaxRepeatedSegments != 0 && maxRepeatedSegment(u.Path) > r.MaxRepeatedSegments {
+		return TrapRepeatedSegments
+	}
+
+	return ""
+}
+
+// hasSessionID reports whether the URL has a session query parameter or a path parameter like ";jsessionid=".
+func (r TrapRules) hasSessionID(u *url.URL) bool {
+	isSessionParam := func(name string) bool {
+		return slices.ContainsFunc(r.SessionParams, func(param string) bool { return strings.EqualFold(param, name) })
+	}
+	for name := range u.Query() {
+		if isSessionParam(name) {
+			return true
+		}
+	}
+
+	_, params, ok := strings.Cut(u.Path, ";")
+	if !ok {
+		return false
+	}
+	name, _, _ := strings.Cut(params, "=")
+
+	return isSessionParam(name)
+}
+
+// trapDetector checks links of a single crawl against TrapRules.
+type trapDetector struct {
+	rules     TrapRules
+	hostPages map[string]int
+	queries   map[string]map[string]bool
+}
+
+func newTrapDetector(rules TrapRules) *trapDetector {
+	return &trapDetector{
+		rules:     rules,
+		hostPages: map[string]int{},
+		queries:   map[string]map[string]bool{},
+	}
+}
+
+// check returns the rule tripped by the link, an empty string is returned for links which may be fetched.
+// Links which pass are counted in the host budget and query combinations.
+func (d *trapDetector) check(link string) string {
+	if rule := d.rules.Trap(link); rule != "" {
+		return rule
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	path := u.Host + u.Path
+	query := u.Query().Encode()
+	queries := d.queries[path]
+	if query != "" && !queries[query] && d.rules.MaxQueryCombinations != 0 && len(queries) >= d.rules.MaxQueryCombinations {
+		return TrapQueryCombinations
+	}
+	if d.rules.MaxPagesPerHost != 0 && d.hostPages[u.Host] >= d.rules.MaxPagesPerHost {
+		return TrapHostBudget
+	}
+
+	if query != "" {
+		if queries == nil {
+			queries = map[string]bool{}
+			d.queries[path] = queries
+		}
+		queries[query] = true
+	}
+	d.hostPages[u.Host]++
+
+	return ""
+}
+
+// maxRepeatedSegment returns the number of occurrences of the most frequent path segment.
+func maxRepeatedSegment(path string) int {
+	counts := map[string]int{}
+	result := 0
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		counts[segment]++
+		result = max(result, counts[segment])
+	}
+
+	return result
+}
@@ -0,0 +1,131 @@
+package web_test
+
+import (
+	"bytes"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/webtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCrawler_ScrapePagesTraps(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rules       func(rules *web.TrapRules)
+		links       []string
+		quarantined map[string]string
+	}{
+		{
+			name:  "should quarantine long urls",
+			rules: func(rules *web.TrapRules) { rules.MaxURLLength = 60 },
+			links: []string{"/short", "/" + strings.Repeat("long", 20)},
+			quarantined: map[string]string{
+				"/" + strings.Repeat("long", 20): web.TrapURLTooLong,
+			},
+		},
+		{
+			name:  "should quarantine urls with repeated path segments",
+			links: []string{"/calendar/2024/calendar/2024/calendar", "/calendar/2024/calendar/2024/calendar/2024/calendar"},
+			quarantined: map[string]string{
+				"/calendar/2024/calendar/2024/calendar/2024/calendar": web.TrapRepeatedSegments,
+			},
+		},
+		{
+			name:  "should quarantine query combinations of a path over the limit",
+			rules: func(rules *web.TrapRules) { rules.MaxQueryCombinations = 2 },
+			links: []string{"/shop?color=red", "/shop?size=m&color=red", "/shop?color=red&size=m", "/shop?color=blue", "/other?color=blue"},
+			quarantined: map[string]string{
+				"/shop?color=blue": web.TrapQueryCombinations,
+			},
+		},
+		{
+			name:  "should quarantine pages over the host budget",
+			rules: func(rules *web.TrapRules) { rules.MaxPagesPerHost = 3 },
+			links: []string{"/1", "/2", "/3", "/4"},
+			quarantined: map[string]string{
+				"/3": web.TrapHostBudget,
+				"/4": web.TrapHostBudget,
+			},
+		},
+		{
+			name:  "should quarantine urls with session ids",
+			links: []string{"/page?id=1", "/page?PHPSESSID=abc", "/cart;jsessionid=abc"},
+			quarantined: map[string]string{
+				"/page?PHPSESSID=abc":  web.TrapSessionID,
+				"/cart;jsessionid=abc": web.TrapSessionID,
+			},
+		},
+		{
+			name:  "should not check disabled rules",
+			rules: func(rules *web.TrapRules) { *rules = web.TrapRules{} },
+			links: []string{"/a/a/a/a/a", "/page?sid=1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			site := webtest.Site{Pages: map[string]webtest.Page{"/": {Links: tc.links}}}
+			for _, link := range tc.links {
+				u, err := url.Parse(link)
+				require.NoError(t, err)
+				site.Pages[u.Path] = webtest.Page{Title: "page"}
+			}
+			server := webtest.NewServer(t, site)
+
+			rules := web.DefaultTrapRules()
+			if tc.rules != nil {
+				tc.rules(&rules)
+			}
+			o := &fetchOrderObserver{}
+			s := web.NewCrawler(web.WithTrapRules(rules), web.WithObserver(o), web.WithWorkers(1))
+
+			// when
+			pages, err := s.ScrapePages(server.Link("/"))
+
+			// expected
+			require.NoError(t, err)
+
+			quarantined := map[string]string{}
+			for link, rule := range s.Quarantine() {
+				quarantined[server.Path(link)] = rule
+			}
+			if tc.quarantined == nil {
+				tc.quarantined = map[string]string{}
+			}
+			assert.Equal(t, tc.quarantined, quarantined)
+			assert.Len(t, pages, 1+len(tc.links)-len(tc.quarantined))
+
+			skipped := make([]string, 0)
+			for link, rule := range tc.quarantined {
+				skipped = append(skipped, server.Link(link)+": "+web.SkipReasonTrap+rule)
+				webtest.AssertNotVisited(t, server, link)
+			}
+			assert.ElementsMatch(t, skipped, o.skipped)
+		})
+	}
+}
+
+func TestCrawler_ScrapePagesTrapsLog(t *testing.T) {
+	// given
+	logs := &bytes.Buffer{}
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+	site := webtest.NewServer(t, webtest.Site{Pages: map[string]webtest.Page{
+		"/":     {Links: []string{"/page", "/page?sid=1"}},
+		"/page": {Title: "Page"},
+	}})
+
+	// when
+	_, err := web.NewCrawler().ScrapePages(site.Link("/"))
+
+	// expected
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), `msg="link quarantined" url="`+site.Link("/page?sid=1")+`" reason="`+web.SkipReasonTrap+web.TrapSessionID+`"`)
+	assert.Equal(t, 1, strings.Count(logs.String(), "\n"), "only quarantined links must be logged by default")
+}
@@ -0,0 +1,169 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/net/html"
+	"io"
+	"slices"
+	"strings"
+)
+
+// voidElements can't have children, so they never become the current parent while building a tree.
+var voidElements = []string{
+	"area", "base", "br", "col", "embed", "hr", "img", "input",
+	"link", "meta", "param", "source", "track", "wbr",
+}
+
+// impliedEndTags lists tags which are closed implicitly when the key tag is opened.
+// E.g. <li> closes the previous <li>.
+var impliedEndTags = map[string][]string{
+	"li":     {"li"},
+	"p":      {"p"},
+	"option": {"option"},
+	"dt":     {"dt", "dd"},
+	"dd":     {"dt", "dd"},
+	"tr":     {"tr", "td", "th"},
+	"td":     {"td", "th"},
+	"th":     {"td", "th"},
+}
+
+// ParseTree builds a tree of Tags from an HTML page.
+//
+// Returned Tag has Document type, its Children are top level page elements.
+// Elements have either OpenTag or SelfCloseTag type, text inside elements is stored as Body Children.
+// Whitespace-only text and comments are skipped.
+func (c *Client) ParseTree(body io.ReadCloser) (*Tag, error) {
+	root := &Tag{Type: Document, Attributes: map[string]string{}}
+	current := root
+
+	token := html.NewTokenizer(body)
+	for tokenType := token.Next(); tokenType != html.ErrorToken; tokenType = token.Next() {
+		switch tokenType {
+		case html.CommentToken:
+			continue
+		case html.TextToken:
+			text := string(token.Text())
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			current.appendChild(&Tag{
+				Body:       text,
+				Type:       Body,
+				Raw:        slices.Clone(token.Raw()),
+				Attributes: map[string]string{},
+			})
+		case html.DoctypeToken, html.SelfClosingTagToken, html.StartTagToken:
+			tagName, hasAttributes := token.TagName()
+			tag := &Tag{
+				Name:       string(tagName),
+				Body:       string(token.Text()),
+				Raw:        slices.Clone(token.Raw()),
+				Attributes: map[string]string{},
+			}
+			tag.provideType(tokenType)
+			for hasAttributes {
+				var k, v []byte
+				k, v, hasAttributes = token.TagAttr()
+				tag.addAttribute(string(k), string(v))
+			}
+
+			if tag.Type == OpenTag {
+				for current.Type == OpenTag && slices.Contains(impliedEndTags[tag.Name], current.Name) {
+					current = current.Parent
+				}
+			}
+			current.appendChild(tag)
+
+			if tag.Type == OpenTag && !slices.Contains(voidElements, tag.Name) {
+				current = tag
+			}
+		case html.EndTagToken:
+			tagName, _ := token.TagName()
+			for open := current; open.Type != Document; open = open.Parent {
+				if open.Name == string(tagName) {
+					current = open.Parent
+					break
+				}
+			}
+		}
+	}
+
+	if err := token.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return root, fmt.Errorf("cannot parse page tree: %w", err)
+	}
+
+	return root, nil
+}
+
+func (t *Tag) appendChild(child *Tag) {
+	child.Parent = t
+	t.Children = append(t.Children, child)
+}
+
+// IsElement reports whether the Tag is an HTML element, i.e. it can be matched by a Selector.
+func (t *Tag) IsElement() bool {
+	return t.Type == OpenTag || t.Type == SelfCloseTag
+}
+
+// ID returns the value of the id attribute.
+func (t *Tag) ID() string {
+	return t.Attributes["id"]
+}
+
+// HasClass reports whether class is listed in the class attribute.
+func (t *Tag) HasClass(class string) bool {
+	return slices.Contains(strings.Fields(t.Attributes["class"]), class)
+}
+
+// Walk calls fn for the Tag and all its descendants in document order.
+// Children of a Tag are skipped if fn returns false for it.
+func (t *Tag) Walk(fn func(tag *Tag) bool) {
+	if !fn(t) {
+		return
+	}
+
+	for _, child := range t.Children {
+		child.Walk(fn)
+	}
+}
+
+// Text returns all text inside the Tag joined with spaces.
+func (t *Tag) Text() string {
+	parts := make([]string, 0)
+	t.Walk(func(tag *Tag) bool {
+		if tag.Type == Body {
+			if text := strings.TrimSpace(tag.Body); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return true
+	})
+
+	return strings.Join(parts, " ")
+}
+
+// Find returns descendants of the Tag matched by CSS selector in document order.
+func (t *Tag) Find(selector string) ([]*Tag, error) {
+	s, err := CompileSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Select(s), nil
+}
+
+// Select returns descendants of the Tag matched by the Selector in document order.
+func (t *Tag) Select(s Selector) []*Tag {
+	result := make([]*Tag, 0)
+	for _, child := range t.Children {
+		child.Walk(func(tag *Tag) bool {
+			if s.Match(tag) {
+				result = append(result, tag)
+			}
+			return true
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,40 @@
+package webtest
+
+import (
+	"slices"
+	"testing"
+)
+
+// AssertVisited checks that exactly pages by paths were requested, in any order.
+func AssertVisited(t testing.TB, s *Server, paths ...string) {
+	t.Helper()
+
+	visited := slices.Clone(s.Visited())
+	expected := slices.Clone(paths)
+	slices.Sort(visited)
+	slices.Sort(expected)
+	if !slices.Equal(visited, expected) {
+		t.Errorf("webtest: visited pages differ\nexpected: %q\nactual:   %q", expected, visited)
+	}
+}
+
+// AssertVisitedInOrder checks that exactly pages by paths were requested in the given order.
+func AssertVisitedInOrder(t testing.TB, s *Server, paths ...string) {
+	t.Helper()
+
+	if visited := s.Visited(); !slices.Equal(visited, paths) {
+		t.Errorf("webtest: visited pages or their order differ\nexpected: %q\nactual:   %q", paths, visited)
+	}
+}
+
+// AssertNotVisited checks that none of pages by paths were requested.
+func AssertNotVisited(t testing.TB, s *Server, paths ...string) {
+	t.Helper()
+
+	visited := s.Visited()
+	for _, path := range paths {
+		if slices.Contains(visited, path) {
+			t.Errorf("webtest: page %s must not be visited, visited: %q", path, visited)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package webtest
+
+// encoders encode page markup by charset names, runes which can't be encoded are replaced with "?".
+var encoders = map[string]func(text string) []byte{
+	"utf-8": func(text string) []byte {
+		return []byte(text)
+	},
+	"iso-8859-1":   encodeLatin1,
+	"latin1":       encodeLatin1,
+	"windows-1251": encodeWindows1251,
+	"cp1251":       encodeWindows1251,
+}
+
+func encodeLatin1(text string) []byte {
+	result := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r > 0xff {
+			r = '?'
+		}
+		result = append(result, byte(r))
+	}
+
+	return result
+}
+
+// windows1251 are windows-1251 bytes of non-ASCII letters used in Russian and Ukrainian.
+var windows1251 = map[rune]byte{
+	'Ё': 0xa8, 'ё': 0xb8, 'Є': 0xaa, 'є': 0xba, 'І': 0xb2, 'і': 0xb3, 'Ї': 0xaf, 'ї': 0xbf, 'Ґ': 0xa5, 'ґ': 0xb4,
+	'«': 0xab, '»': 0xbb, '—': 0x97, '–': 0x96, '№': 0xb9,
+}
+
+func encodeWindows1251(text string) []byte {
+	result := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch b, ok := windows1251[r]; {
+		case r < 0x80:
+			result = append(result, byte(r))
+		case ok:
+			result = append(result, b)
+		case r >= 'А' && r <= 'я':
+			result = append(result, byte(r-'А'+0xc0))
+		default:
+			result = append(result, '?')
+		}
+	}
+
+	return result
+}
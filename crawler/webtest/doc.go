@@ -0,0 +1,11 @@
+// Package webtest provides API for fake websites used in crawler tests.
+//
+// A Site is described declaratively by its pages, and NewServer serves it by an httptest server:
+//
+//	s := webtest.NewServer(t, webtest.Site{Pages: map[string]webtest.Page{
+//		"/":      {Title: "Home", Links: []string{"/about"}},
+//		"/about": {Title: "About", Text: []string{"About us."}},
+//	}})
+//	_, err := web.NewCrawler(web.WithWorkers(1)).ScrapePages(s.Link("/"))
+//	webtest.AssertVisitedInOrder(t, s, "/", "/about")
+package webtest
@@ -0,0 +1,248 @@
+package webtest
+
+import (
+	"fmt"
+	"html"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	RobotsPath  = "/robots.txt"
+	SitemapPath = "/sitemap.xml"
+)
+
+// Site is a description of a fake website.
+type Site struct {
+	// Pages are pages by their paths, other paths are answered with 404 Not Found.
+	Pages map[string]Page
+	// Robots is the content of robots.txt, it isn't served when empty.
+	Robots string
+	// Sitemap serves sitemap.xml with all pages except redirects and errors.
+	Sitemap bool
+	// Charset is the charset of all pages, utf-8 is used by default.
+	Charset string
+}
+
+// Page is a description of a fake page.
+//
+// The page markup is generated from Title, Text and Links unless HTML is set.
+type Page struct {
+	Title string
+	// Text are paragraphs of the page.
+	Text []string
+	// Links are paths or absolute URLs the page links to, the link text is its href.
+	Links []string
+	// HTML is served as is instead of the generated markup.
+	HTML string
+	// Status is the response status, 200 OK is used by default.
+	Status int
+	// RedirectTo redirects to the path with Status, 302 Found is used when Status isn't a redirect one.
+	RedirectTo string
+	// Delay is a time to wait before the response.
+	Delay time.Duration
+	// Header are additional response headers.
+	Header map[string]string
+	// Charset overrides the Site charset: utf-8, iso-8859-1 and windows-1251 are supported.
+	Charset string
+	// Priority is the sitemap priority of the page, it's omitted when zero.
+	Priority float64
+}
+
+// Server serves a Site and records requests to it.
+type Server struct {
+	*httptest.Server
+
+	site     Site
+	mutex    *sync.Mutex
+	requests []string
+}
+
+// NewServer starts a Server of the site, it's closed when the test is finished.
+func NewServer(t testing.TB, site Site) *Server {
+	t.Helper()
+
+	for path, page := range site.Pages {
+		if _, ok := encoders[charset(site, page)]; !ok {
+			t.Fatalf("webtest: unsupported charset %q of page %s", charset(site, page), path)
+		}
+	}
+
+	site.Pages = maps.Clone(site.Pages)
+	s := &Server{site: site, mutex: &sync.Mutex{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// SetPage adds the page by path or replaces it, e.g. to change the content of a page between crawls.
+func (s *Server) SetPage(t testing.TB, path string, page Page) {
+	t.Helper()
+
+	if _, ok := encoders[charset(s.site, page)]; !ok {
+		t.Fatalf("webtest: unsupported charset %q of page %s", charset(s.site, page), path)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.site.Pages == nil {
+		s.site.Pages = map[string]Page{}
+	}
+	s.site.Pages[path] = page
+}
+
+// Link returns the absolute URL of the path.
+func (s *Server) Link(path string) string {
+	return s.URL + path
+}
+
+// Path returns the path with the query of the URL of the Server, other URLs are returned as is.
+func (s *Server) Path(url string) string {
+	if path := strings.TrimPrefix(url, s.URL); path != url {
+		if path == "" {
+			return "/"
+		}
+		return path
+	}
+
+	return url
+}
+
+// Requests returns paths with queries of all requests in the order they were received,
+// including requests of robots.txt, sitemap.xml and missing pages.
+func (s *Server) Requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+// Visited returns paths of requested pages except robots.txt and sitemap.xml in the order they were requested.
+func (s *Server) Visited() []string {
+	return slices.DeleteFunc(s.Requests(), func(path string) bool {
+		return path == RobotsPath || path == SitemapPath
+	})
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	s.mutex.Unlock()
+
+	switch {
+	case r.URL.Path == RobotsPath && s.site.Robots != "":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = fmt.Fprint(w, s.site.Robots)
+		return
+	case r.URL.Path == SitemapPath && s.site.Sitemap:
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		_, _ = fmt.Fprint(w, s.sitemap())
+		return
+	}
+
+	page, ok := s.page(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	time.Sleep(page.Delay)
+	for name, value := range page.Header {
+		w.Header().Set(name, value)
+	}
+
+	if page.RedirectTo != "" {
+		status := page.Status
+		if status < http.StatusMultipleChoices || status >= http.StatusBadRequest {
+			status = http.StatusFound
+		}
+		http.Redirect(w, r, page.RedirectTo, status)
+		return
+	}
+
+	cs := charset(s.site, page)
+	w.Header().Set("Content-Type", "text/html; charset="+cs)
+	if page.Status != 0 {
+		w.WriteHeader(page.Status)
+	}
+	_, _ = w.Write(encoders[cs](page.markup(cs)))
+}
+
+// page returns the page by the request URI, or by the path when there is no page of the URI.
+func (s *Server) page(r *http.Request) (Page, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	page, ok := s.site.Pages[r.URL.RequestURI()]
+	if !ok {
+		page, ok = s.site.Pages[r.URL.Path]
+	}
+
+	return page, ok
+}
+
+func (p Page) markup(charset string) string {
+	if p.HTML != "" {
+		return p.HTML
+	}
+
+	b := &strings.Builder{}
+	_, _ = fmt.Fprintf(b, `<!DOCTYPE html><html><head><meta charset="%s"><title>%s</title></head><body>`,
+		charset, html.EscapeString(p.Title))
+	for _, text := range p.Text {
+		_, _ = fmt.Fprintf(b, "<p>%s</p>", html.EscapeString(text))
+	}
+	for _, link := range p.Links {
+		_, _ = fmt.Fprintf(b, `<a href="%[1]s">%[1]s</a>`, html.EscapeString(link))
+	}
+	b.WriteString("</body></html>")
+
+	return b.String()
+}
+
+func (s *Server) sitemap() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	paths := make([]string, 0, len(s.site.Pages))
+	for path, page := range s.site.Pages {
+		if page.RedirectTo == "" && (page.Status == 0 || page.Status == http.StatusOK) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	b := &strings.Builder{}
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + "\n")
+	for _, path := range paths {
+		_, _ = fmt.Fprintf(b, "<url><loc>%s</loc>", html.EscapeString(s.Link(path)))
+		if priority := s.site.Pages[path].Priority; priority != 0 {
+			_, _ = fmt.Fprintf(b, "<priority>%.1f</priority>", priority)
+		}
+		b.WriteString("</url>\n")
+	}
+	b.WriteString("</urlset>\n")
+
+	return b.String()
+}
+
+func charset(site Site, page Page) string {
+	cs := page.Charset
+	if cs == "" {
+		cs = site.Charset
+	}
+	if cs == "" {
+		cs = "utf-8"
+	}
+
+	return strings.ToLower(cs)
+}
@@ -0,0 +1,261 @@
+package webtest_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
+	"github.com/mishaprokop4ik/gorecs-search/crawler/webtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	site := webtest.Site{
+		Pages: map[string]webtest.Page{
+			"/":       {Title: "Home", Text: []string{"Welcome home."}, Links: []string{"/a", "/b", "/old"}},
+			"/a":      {Title: "A", Links: []string{"/a/1", "/missing"}},
+			"/b":      {Title: "B", Status: http.StatusInternalServerError},
+			"/a/1":    {Title: "A1", Priority: 0.5},
+			"/old":    {RedirectTo: "/a/1", Status: http.StatusMovedPermanently},
+			"/slow":   {Title: "Slow", Delay: 50 * time.Millisecond},
+			"/latin":  {Title: "Café", Charset: "iso-8859-1"},
+			"/cyr":    {Title: "Привіт, світе", Charset: "windows-1251"},
+			"/custom": {HTML: "<p>raw</p>", Header: map[string]string{"X-Test": "yes"}},
+		},
+		Robots:  "User-agent: *\nDisallow: /b\n",
+		Sitemap: true,
+	}
+	s := webtest.NewServer(t, site)
+
+	get := func(t *testing.T, path string) (*http.Response, string) {
+		resp, err := http.Get(s.Link(path))
+		require.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp, string(body)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+		contains    []string
+	}{
+		{
+			name:        "should generate markup of a page",
+			path:        "/",
+			status:      http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			contains:    []string{"<title>Home</title>", "<p>Welcome home.</p>", `<a href="/a">/a</a>`},
+		},
+		{
+			name:   "should answer with the page status",
+			path:   "/b",
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "should answer missing pages with not found",
+			path:   "/nowhere",
+			status: http.StatusNotFound,
+		},
+		{
+			name:     "should follow redirects",
+			path:     "/old",
+			status:   http.StatusOK,
+			contains: []string{"<title>A1</title>"},
+		},
+		{
+			name:        "should encode pages with latin-1",
+			path:        "/latin",
+			status:      http.StatusOK,
+			contentType: "text/html; charset=iso-8859-1",
+			contains:    []string{"Caf\xe9", `<meta charset="iso-8859-1">`},
+		},
+		{
+			name:        "should encode pages with windows-1251",
+			path:        "/cyr",
+			status:      http.StatusOK,
+			contentType: "text/html; charset=windows-1251",
+			contains:    []string{"\xcf\xf0\xe8\xe2\xb3\xf2, \xf1\xe2\xb3\xf2\xe5"},
+		},
+		{
+			name:     "should serve robots.txt",
+			path:     webtest.RobotsPath,
+			status:   http.StatusOK,
+			contains: []string{"Disallow: /b"},
+		},
+		{
+			name:   "should list pages in sitemap.xml",
+			path:   webtest.SitemapPath,
+			status: http.StatusOK,
+			contains: []string{
+				"<url><loc>" + s.Link("/a/1") + "</loc><priority>0.5</priority></url>",
+				"<url><loc>" + s.Link("/") + "</loc></url>",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			resp, body := get(t, tt.path)
+
+			// expected
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, resp.Header.Get("Content-Type"))
+			}
+			for _, s := range tt.contains {
+				assert.Contains(t, body, s)
+			}
+		})
+	}
+
+	t.Run("should not list redirects and failed pages in sitemap.xml", func(t *testing.T) {
+		// when
+		_, body := get(t, webtest.SitemapPath)
+
+		// expected
+		assert.NotContains(t, body, s.Link("/old"))
+		assert.NotContains(t, body, s.Link("/b"))
+	})
+
+	t.Run("should serve a changed page", func(t *testing.T) {
+		// given
+		s.SetPage(t, "/changing", webtest.Page{Title: "Before"})
+		_, before := get(t, "/changing")
+
+		// when
+		s.SetPage(t, "/changing", webtest.Page{Title: "After"})
+		_, after := get(t, "/changing")
+
+		// expected
+		assert.Contains(t, before, "<title>Before</title>")
+		assert.Contains(t, after, "<title>After</title>")
+	})
+
+	t.Run("should serve raw HTML with headers", func(t *testing.T) {
+		// when
+		resp, body := get(t, "/custom")
+
+		// expected
+		assert.Equal(t, "<p>raw</p>", body)
+		assert.Equal(t, "yes", resp.Header.Get("X-Test"))
+	})
+
+	t.Run("should delay the response", func(t *testing.T) {
+		// given
+		start := time.Now()
+
+		// when
+		_, _ = get(t, "/slow")
+
+		// expected
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("should record requests in order", func(t *testing.T) {
+		// expected
+		requests := s.Requests()
+		assert.Equal(t, []string{"/", "/b", "/nowhere", "/old", "/a/1"}, requests[:5])
+		assert.Contains(t, requests, webtest.RobotsPath)
+		assert.NotContains(t, s.Visited(), webtest.RobotsPath)
+		assert.NotContains(t, s.Visited(), webtest.SitemapPath)
+	})
+}
+
+func TestServer_Crawl(t *testing.T) {
+	// given
+	s := webtest.NewServer(t, webtest.Site{Pages: map[string]webtest.Page{
+		"/":     {Title: "Home", Links: []string{"/a", "/b"}},
+		"/a":    {Title: "A", Text: []string{"Page A."}, Links: []string{"/a/1", "/"}},
+		"/b":    {Title: "B", Links: []string{"/missing"}},
+		"/a/1":  {Title: "A1", Text: []string{"Page A1."}},
+		"/skip": {Title: "Unlinked"},
+	}})
+
+	// when
+	pages, err := web.NewCrawler(web.WithWorkers(1), web.WithMaxDepth(3)).ScrapePages(s.Link("/"))
+
+	// expected
+	require.NoError(t, err)
+	assert.Contains(t, pages, s.Link("/a/1"))
+	assert.Equal(t, "/a/1", s.Path(s.Link("/a/1")))
+	assert.Equal(t, "/", s.Path(s.URL))
+	webtest.AssertVisited(t, s, "/", "/b", "/a", "/missing", "/a/1")
+	webtest.AssertVisitedInOrder(t, s, "/", "/a", "/b", "/a/1", "/missing")
+	webtest.AssertNotVisited(t, s, "/skip")
+}
+
+func TestAssertVisited(t *testing.T) {
+	// given
+	s := webtest.NewServer(t, webtest.Site{Pages: map[string]webtest.Page{"/": {}, "/a": {}}})
+	for _, path := range []string{"/a", "/"} {
+		resp, err := http.Get(s.Link(path))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		failed bool
+	}{
+		{
+			name:   "should pass when the same pages are visited in any order",
+			assert: func(t testing.TB) { webtest.AssertVisited(t, s, "/", "/a") },
+		},
+		{
+			name:   "should fail when other pages are visited",
+			assert: func(t testing.TB) { webtest.AssertVisited(t, s, "/") },
+			failed: true,
+		},
+		{
+			name:   "should pass when pages are visited in the order",
+			assert: func(t testing.TB) { webtest.AssertVisitedInOrder(t, s, "/a", "/") },
+		},
+		{
+			name:   "should fail when pages are visited in another order",
+			assert: func(t testing.TB) { webtest.AssertVisitedInOrder(t, s, "/", "/a") },
+			failed: true,
+		},
+		{
+			name:   "should fail when the page is visited",
+			assert: func(t testing.TB) { webtest.AssertNotVisited(t, s, "/b", "/a") },
+			failed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			recorder := &recordingTB{TB: t}
+
+			// when
+			tt.assert(recorder)
+
+			// expected
+			assert.Equal(t, tt.failed, recorder.failed, strings.Join(recorder.errors, "\n"))
+		})
+	}
+}
+
+// recordingTB records failures instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	failed bool
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failed = true
+	r.errors = append(r.errors, format)
+}
@@ -0,0 +1,94 @@
+package language_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/language"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDetector_Detect(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "should detect english",
+			text:     "Generics let you write functions and types that work with any of a set of types.",
+			expected: "en",
+		},
+		{
+			name:     "should detect ukrainian",
+			text:     "Узагальнені типи дозволяють писати функції, які працюють з будь-яким набором типів.",
+			expected: "uk",
+		},
+		{
+			name:     "should detect russian",
+			text:     "Обобщённые типы позволяют писать функции, которые работают с любым набором типов.",
+			expected: "ru",
+		},
+		{
+			name:     "should detect german",
+			text:     "Generische Typen erlauben es, Funktionen zu schreiben, die mit einer Menge von Typen arbeiten.",
+			expected: "de",
+		},
+		{
+			name:     "should detect french",
+			text:     "Les types génériques permettent d'écrire des fonctions qui marchent avec un ensemble de types.",
+			expected: "fr",
+		},
+		{
+			name:     "should detect spanish",
+			text:     "Los tipos genéricos permiten escribir funciones que trabajan con un conjunto de tipos.",
+			expected: "es",
+		},
+		{
+			name:     "should not detect languages of unknown scripts",
+			text:     "搜索引擎抓取网页并建立单词索引，用户可以快速找到需要的文档。",
+			expected: language.Unknown,
+		},
+		{
+			name: "should not detect unknown languages of a known script",
+			text: "Il motore di ricerca visita le pagine del sito, estrae il loro testo e costruisce un indice delle parole. " +
+				"Quando un utente scrive una richiesta, il motore cerca le parole nell'indice e ordina i documenti secondo la loro pertinenza.",
+			expected: language.Unknown,
+		},
+		{
+			name:     "should not detect too short texts",
+			text:     "Go 1.22",
+			expected: language.Unknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, language.Detect(tc.text))
+		})
+	}
+}
+
+func TestNewDetector(t *testing.T) {
+	// given
+	d := language.NewDetector(map[string]string{
+		"aa": "aaa aab aba baa aaa aab aba baa aaa aab aba baa",
+		"bb": "bbb bba bab abb bbb bba bab abb bbb bba bab abb",
+	})
+
+	// expected
+	assert.Equal(t, []string{"aa", "bb"}, d.Languages())
+	assert.Equal(t, "bb", d.Detect("babb abbb bbab bbba babb abbb"))
+}
+
+func TestNormalize(t *testing.T) {
+	testCases := map[string]string{
+		"en":          "en",
+		"en-US":       "en",
+		"uk_UA":       "uk",
+		" DE-de, en ": "de",
+		"":            "",
+	}
+
+	for tag, expected := range testCases {
+		assert.Equal(t, expected, language.Normalize(tag), tag)
+	}
+}
@@ -0,0 +1,5 @@
+// Package language provides API for language identification.
+//
+// A language is identified by profiles of character n-grams built from training texts,
+// profiles of en, uk, ru, de, fr and es are built in.
+package language
@@ -0,0 +1,199 @@
+package language
+
+import (
+	"embed"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Unknown is returned when the language can't be identified.
+const Unknown = ""
+
+const (
+	// DefaultProfileSize is a number of the most frequent n-grams kept in a profile.
+	DefaultProfileSize = 300
+	// MinTextLength is the minimal number of letters of a text to identify its language.
+	MinTextLength = 20
+	// maxTextLength is a number of text runes used for detection, the rest of the text is ignored.
+	maxTextLength = 10000
+	maxNGram      = 3
+)
+
+// Texts of unsupported languages are either far from every profile or equally close to several of them.
+// The thresholds are picked by hand, Detect tests check them on texts of known and unknown languages.
+const (
+	// maxDistanceRatio is the largest distance of the best profile relative to the distance of a text sharing no n-grams.
+	maxDistanceRatio = 0.75
+	// minMatchRatio is the smallest part of text n-grams found in the best profile.
+	minMatchRatio = 0.32
+	// minMarginRatio is the smallest gap between the best and the second best distances relative to the best one.
+	minMarginRatio = 0.035
+)
+
+//go:embed profiles/*.txt
+var profiles embed.FS
+
+// Detector identifies languages with the out-of-place distance of n-gram rankings (Cavnar and Trenkle).
+//
+// Every language profile is a ranking of the most frequent 1-3 character n-grams of a training text.
+// A text is ranked the same way and the language with the smallest sum of rank differences wins.
+type Detector struct {
+	profiles map[string]map[string]int
+	size     int
+}
+
+// NewDetector builds language profiles from training texts by language codes.
+func NewDetector(training map[string]string) *Detector {
+	d := &Detector{profiles: make(map[string]map[string]int, len(training)), size: DefaultProfileSize}
+	for lang, text := range training {
+		d.profiles[lang] = rankNGrams(text, d.size)
+	}
+
+	return d
+}
+
+var defaultDetector = sync.OnceValue(func() *Detector {
+	training := map[string]string{}
+	files, _ := profiles.ReadDir("profiles")
+	for _, f := range files {
+		text, err := profiles.ReadFile(path.Join("profiles", f.Name()))
+		if err != nil {
+			continue
+		}
+		training[strings.TrimSuffix(f.Name(), ".txt")] = string(text)
+	}
+
+	return NewDetector(training)
+})
+
+// Default returns the Detector of built-in languages: en, uk, ru, de, fr and es.
+func Default() *Detector {
+	return defaultDetector()
+}
+
+// Detect identifies the language of the text with the Default Detector.
+func Detect(text string) string {
+	return Default().Detect(text)
+}
+
+// Languages returns sorted codes of languages known by the Detector.
+func (d *Detector) Languages() []string {
+	languages := make([]string, 0, len(d.profiles))
+	for lang := range d.profiles {
+		languages = append(languages, lang)
+	}
+	slices.Sort(languages)
+
+	return languages
+}
+
+// Detect returns the language code of the text.
+// Unknown is returned for texts shorter than MinTextLength letters and for texts of languages without a profile.
+func (d *Detector) Detect(text string) string {
+	if runes := []rune(text); len(runes) > maxTextLength {
+		text = string(runes[:maxTextLength])
+	}
+	if countLetters(text) < MinTextLength {
+		return Unknown
+	}
+
+	ranks := rankNGrams(text, d.size)
+	result, best, second, matched := Unknown, math.MaxInt, math.MaxInt, 0
+	for _, lang := range d.Languages() {
+		distance, found := 0, 0
+		for ngram, rank := range ranks {
+			if profileRank, ok := d.profiles[lang][ngram]; ok {
+				distance += abs(rank - profileRank)
+				found++
+			} else {
+				distance += d.size
+			}
+		}
+
+		if distance < best {
+			result, best, second, matched = lang, distance, best, found
+		} else if distance < second {
+			second = distance
+		}
+	}
+
+	maxDistance := float64(len(ranks) * d.size)
+	switch {
+	case float64(best) > maxDistanceRatio*maxDistance,
+		float64(matched) < minMatchRatio*float64(len(ranks)),
+		second != math.MaxInt && float64(second-best) < minMarginRatio*float64(best):
+		return Unknown
+	}
+
+	return result
+}
+
+// Normalize returns the primary language subtag of a language tag, e.g. "en" for "en-US" or "uk_UA".
+// Only the first tag of a list like the Content-Language header value is used.
+func Normalize(tag string) string {
+	tag, _, _ = strings.Cut(tag, ",")
+	tag = strings.TrimSpace(tag)
+	tag, _, _ = strings.Cut(tag, "-")
+	tag, _, _ = strings.Cut(tag, "_")
+
+	return strings.ToLower(tag)
+}
+
+// rankNGrams returns ranks of the most frequent n-grams of the text, words are padded with "_" on both sides.
+func rankNGrams(text string, size int) map[string]int {
+	counts := map[string]int{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, word := range words {
+		runes := []rune("_" + strings.Trim(word, "'") + "_")
+		for n := 1; n <= maxNGram; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				if ngram := string(runes[i : i+n]); ngram != "_" {
+					counts[ngram]++
+				}
+			}
+		}
+	}
+
+	ngrams := make([]string, 0, len(counts))
+	for ngram := range counts {
+		ngrams = append(ngrams, ngram)
+	}
+	slices.SortFunc(ngrams, func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	ranks := make(map[string]int, min(size, len(ngrams)))
+	for i, ngram := range ngrams[:min(size, len(ngrams))] {
+		ranks[ngram] = i
+	}
+
+	return ranks
+}
+
+func countLetters(text string) int {
+	n := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
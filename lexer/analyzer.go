@@ -0,0 +1,264 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Names of built-in analyzers.
+const (
+	// StandardAnalyzer splits text by the Lexer and lowercases tokens, it's the default analyzer.
+	StandardAnalyzer = "standard"
+	// WhitespaceAnalyzer splits text by whitespace and keeps tokens as they are.
+	WhitespaceAnalyzer = "whitespace"
+	// KeywordAnalyzer keeps the whole text as a single lowercased token, e.g. for author names.
+	KeywordAnalyzer = "keyword"
+	// SmartAnalyzer splits text by the SmartTokenizer and lowercases tokens.
+	SmartAnalyzer = "smart"
+)
+
+var ErrUnknownAnalyzer = errors.New("unknown analyzer")
+
+// Tokenizer splits a text into tokens.
+type Tokenizer interface {
+	Tokenize(text string) []Token
+}
+
+// TokenizerFunc is a function adapter of Tokenizer.
+type TokenizerFunc func(text string) []Token
+
+func (f TokenizerFunc) Tokenize(text string) []Token {
+	return f(text)
+}
+
+// TokenFilter transforms tokens of a Tokenizer, e.g. lowercases, removes or stems them.
+// Filters may change tokens in place, as tokens are created for every analyzed text.
+type TokenFilter interface {
+	Filter(tokens []Token) []Token
+}
+
+// TokenFilterFunc is a function adapter of TokenFilter.
+type TokenFilterFunc func(tokens []Token) []Token
+
+func (f TokenFilterFunc) Filter(tokens []Token) []Token {
+	return f(tokens)
+}
+
+// Analyzer turns a text into terms: the text is split by the Tokenizer and tokens are passed through Filters in order.
+// The same Analyzer must be used for documents and queries, otherwise their terms don't match.
+type Analyzer struct {
+	Tokenizer Tokenizer
+	Filters   []TokenFilter
+}
+
+// NewAnalyzer creates an Analyzer of the tokenizer followed by filters.
+func NewAnalyzer(tokenizer Tokenizer, filters ...TokenFilter) *Analyzer {
+	return &Analyzer{Tokenizer: tokenizer, Filters: filters}
+}
+
+// Analyze returns terms of the text.
+func (a *Analyzer) Analyze(text string) []string {
+	tokens := a.Tokens(text)
+	terms := make([]string, len(tokens))
+	for i, token := range tokens {
+		terms[i] = token.Text
+	}
+
+	return terms
+}
+
+// Tokens returns tokens of the text with their positions and offsets.
+func (a *Analyzer) Tokens(text string) []Token {
+	tokens := a.Tokenizer.Tokenize(text)
+	for _, filter := range a.Filters {
+		tokens = filter.Filter(tokens)
+	}
+
+	return tokens
+}
+
+// With returns a copy of the Analyzer with filters appended to its Filters.
+func (a *Analyzer) With(filters ...TokenFilter) *Analyzer {
+	return NewAnalyzer(a.Tokenizer, append(slices.Clip(a.Filters), filters...)...)
+}
+
+// Prepend returns a copy of the Analyzer with filters placed before its Filters.
+func (a *Analyzer) Prepend(filters ...TokenFilter) *Analyzer {
+	return NewAnalyzer(a.Tokenizer, append(slices.Clip(filters), a.Filters...)...)
+}
+
+// StandardTokenizer splits text into words, numbers and symbols like the Lexer, but keeps the case of tokens.
+func StandardTokenizer() Tokenizer {
+	return TokenizerFunc(func(text string) []Token {
+		l := NewLexer(text)
+		tokens := make([]Token, 0)
+		for token, ok := l.next(); ok; token, ok = l.next() {
+			tokens = append(tokens, token)
+		}
+
+		return tokens
+	})
+}
+
+// WhitespaceTokenizer splits text by whitespace, all tokens are WordToken.
+func WhitespaceTokenizer() Tokenizer {
+	return TokenizerFunc(func(text string) []Token {
+		l := NewLexer(text)
+		tokens := make([]Token, 0)
+		for {
+			l.chopWhile(unicode.IsSpace)
+			if len(l.Terms) == 0 {
+				return tokens
+			}
+
+			token := Token{Position: len(tokens), Start: l.offset, StartRune: l.runeOffset}
+			token.Text = string(l.chopWhile(func(c rune) bool { return !unicode.IsSpace(c) }))
+			token.End, token.EndRune = l.offset, l.runeOffset
+			tokens = append(tokens, token)
+		}
+	})
+}
+
+// KeywordTokenizer returns the whole trimmed text as a single WordToken, nothing is returned for a blank text.
+func KeywordTokenizer() Tokenizer {
+	return TokenizerFunc(func(text string) []Token {
+		trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+		start := len(text) - len(trimmed)
+		if trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace); trimmed == "" {
+			return []Token{}
+		}
+
+		startRune := utf8.RuneCountInString(text[:start])
+		return []Token{{
+			Text:      trimmed,
+			Start:     start,
+			End:       start + len(trimmed),
+			StartRune: startRune,
+			EndRune:   startRune + utf8.RuneCountInString(trimmed),
+		}}
+	})
+}
+
+// LowercaseFilter lowercases tokens.
+func LowercaseFilter() TokenFilter {
+	return TokenFilterFunc(func(tokens []Token) []Token {
+		for i := range tokens {
+			tokens[i].Text = strings.ToLower(tokens[i].Text)
+		}
+
+		return tokens
+	})
+}
+
+// LengthFilter removes tokens shorter than minLength or longer than maxLength runes, zero maxLength is unlimited.
+func LengthFilter(minLength, maxLength int) TokenFilter {
+	return TokenFilterFunc(func(tokens []Token) []Token {
+		return slices.DeleteFunc(tokens, func(token Token) bool {
+			n := utf8.RuneCountInString(token.Text)
+			return n < minLength || (maxLength > 0 && n > maxLength)
+		})
+	})
+}
+
+// stemFilter replaces texts of words with their stems, numbers, emails, URLs and other tokens are kept as is.
+func stemFilter(stem func(word string) string) TokenFilter {
+	return TokenFilterFunc(func(tokens []Token) []Token {
+		for i := range tokens {
+			switch tokens[i].Type {
+			case WordToken, PossessiveToken, HyphenatedToken, SynonymToken:
+				tokens[i].Text = stem(tokens[i].Text)
+			}
+		}
+
+		return tokens
+	})
+}
+
+var standard = NewAnalyzer(StandardTokenizer(), LowercaseFilter())
+
+var analyzers = struct {
+	mutex  sync.RWMutex
+	byName map[string]*Analyzer
+}{
+	byName: map[string]*Analyzer{
+		StandardAnalyzer:   standard,
+		WhitespaceAnalyzer: NewAnalyzer(WhitespaceTokenizer()),
+		KeywordAnalyzer:    NewAnalyzer(KeywordTokenizer(), LowercaseFilter()),
+		SmartAnalyzer:      NewAnalyzer(SmartTokenizer(), LowercaseFilter()),
+		EnglishAnalyzer:    NewAnalyzer(StandardTokenizer(), LowercaseFilter(), EnglishStemFilter()),
+		RussianAnalyzer:    NewAnalyzer(StandardTokenizer(), LowercaseFilter(), RussianStemFilter()),
+		UkrainianAnalyzer:  NewAnalyzer(StandardTokenizer(), LowercaseFilter(), UkrainianStemFilter()),
+	},
+}
+
+// RegisterAnalyzer registers the analyzer by name, an analyzer registered before by the name is replaced.
+func RegisterAnalyzer(name string, analyzer *Analyzer) {
+	analyzers.mutex.Lock()
+	defer analyzers.mutex.Unlock()
+
+	analyzers.byName[name] = analyzer
+}
+
+// UnregisterAnalyzer removes the analyzer registered by name.
+func UnregisterAnalyzer(name string) {
+	analyzers.mutex.Lock()
+	defer analyzers.mutex.Unlock()
+
+	delete(analyzers.byName, name)
+}
+
+// LookupAnalyzer returns the analyzer registered by name.
+func LookupAnalyzer(name string) (*Analyzer, error) {
+	analyzers.mutex.RLock()
+	defer analyzers.mutex.RUnlock()
+
+	analyzer, ok := analyzers.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAnalyzer, name)
+	}
+
+	return analyzer, nil
+}
+
+// Analyzers returns sorted names of registered analyzers.
+func Analyzers() []string {
+	analyzers.mutex.RLock()
+	defer analyzers.mutex.RUnlock()
+
+	names := make([]string, 0, len(analyzers.byName))
+	for name := range analyzers.byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// languageAnalyzers are names of analyzers by ISO 639-1 codes of their languages.
+var languageAnalyzers = map[string]string{
+	"en": EnglishAnalyzer,
+	"ru": RussianAnalyzer,
+	"uk": UkrainianAnalyzer,
+}
+
+// LanguageAnalyzers returns registered analyzers of languages with stemmers by ISO 639-1 codes, e.g. "en".
+func LanguageAnalyzers() map[string]*Analyzer {
+	result := make(map[string]*Analyzer, len(languageAnalyzers))
+	for lang, name := range languageAnalyzers {
+		if analyzer, err := LookupAnalyzer(name); err == nil {
+			result[lang] = analyzer
+		}
+	}
+
+	return result
+}
+
+// Standard returns the built-in StandardAnalyzer, it splits text the same way as Lexer.Tokens.
+func Standard() *Analyzer {
+	return standard
+}
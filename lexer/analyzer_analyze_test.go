@@ -0,0 +1,149 @@
+package lexer_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer *lexer.Analyzer
+		text     string
+		expected []string
+	}{
+		{
+			name:     "should split text like the lexer with the standard analyzer",
+			analyzer: lexer.Standard(),
+			text:     "Hello, it is Misha. Why are you here...?",
+			expected: lexer.NewLexer("Hello, it is Misha. Why are you here...?").All(),
+		},
+		{
+			name:     "should keep the case of tokens without the lowercase filter",
+			analyzer: lexer.NewAnalyzer(lexer.StandardTokenizer()),
+			text:     "Go Modules",
+			expected: []string{"Go", "Modules"},
+		},
+		{
+			name:     "should split text by whitespace",
+			analyzer: lexer.NewAnalyzer(lexer.WhitespaceTokenizer()),
+			text:     " cgroup.procs  file\n",
+			expected: []string{"cgroup.procs", "file"},
+		},
+		{
+			name:     "should keep the whole text as a keyword",
+			analyzer: lexer.NewAnalyzer(lexer.KeywordTokenizer(), lexer.LowercaseFilter()),
+			text:     "  Rob Pike ",
+			expected: []string{"rob pike"},
+		},
+		{
+			name:     "should return no keyword of a blank text",
+			analyzer: lexer.NewAnalyzer(lexer.KeywordTokenizer()),
+			text:     "  ",
+			expected: []string{},
+		},
+		{
+			name:     "should apply filters in order",
+			analyzer: lexer.Standard().With(lexer.LengthFilter(3, 5)),
+			text:     "A cgroup is in the CPU tree",
+			expected: []string{"the", "cpu", "tree"},
+		},
+		{
+			name: "should apply custom filters",
+			analyzer: lexer.NewAnalyzer(lexer.StandardTokenizer(), lexer.TokenFilterFunc(func(tokens []lexer.Token) []lexer.Token {
+				for i := range tokens {
+					tokens[i].Text = strings.Repeat(tokens[i].Text, 2)
+				}
+				return tokens
+			})),
+			text:     "go go",
+			expected: []string{"gogo", "gogo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			terms := tt.analyzer.Analyze(tt.text)
+
+			// expected
+			assert.Equal(t, tt.expected, terms)
+		})
+	}
+
+	t.Run("should not change filters of the original analyzer", func(t *testing.T) {
+		// given
+		analyzer := lexer.NewAnalyzer(lexer.StandardTokenizer())
+
+		// when
+		_ = analyzer.With(lexer.LowercaseFilter())
+
+		// expected
+		assert.Empty(t, analyzer.Filters)
+	})
+}
+
+func TestLookupAnalyzer(t *testing.T) {
+	t.Run("should return built-in analyzers", func(t *testing.T) {
+		// when
+		analyzer, err := lexer.LookupAnalyzer(lexer.StandardAnalyzer)
+
+		// expected
+		require.NoError(t, err)
+		assert.Same(t, lexer.Standard(), analyzer)
+		assert.Subset(t, lexer.Analyzers(), []string{lexer.StandardAnalyzer, lexer.WhitespaceAnalyzer, lexer.KeywordAnalyzer})
+	})
+
+	t.Run("should return registered analyzers", func(t *testing.T) {
+		// given
+		short := lexer.Standard().With(lexer.LengthFilter(0, 3))
+		lexer.RegisterAnalyzer("short", short)
+		t.Cleanup(func() { lexer.UnregisterAnalyzer("short") })
+
+		// when
+		analyzer, err := lexer.LookupAnalyzer("short")
+
+		// expected
+		require.NoError(t, err)
+		assert.Same(t, short, analyzer)
+		assert.Contains(t, lexer.Analyzers(), "short")
+	})
+
+	t.Run("should not return unregistered analyzers", func(t *testing.T) {
+		// given
+		lexer.RegisterAnalyzer("unregistered", lexer.Standard())
+		lexer.UnregisterAnalyzer("unregistered")
+
+		// when
+		_, err := lexer.LookupAnalyzer("unregistered")
+
+		// expected
+		assert.ErrorIs(t, err, lexer.ErrUnknownAnalyzer)
+		assert.NotContains(t, lexer.Analyzers(), "unregistered")
+	})
+
+	t.Run("should fail for unknown analyzers", func(t *testing.T) {
+		// when
+		_, err := lexer.LookupAnalyzer("unknown")
+
+		// expected
+		assert.ErrorIs(t, err, lexer.ErrUnknownAnalyzer)
+	})
+}
+
+func TestLanguageAnalyzers(t *testing.T) {
+	// given
+	analyzers := lexer.LanguageAnalyzers()
+
+	// when
+	terms := map[string][]string{}
+	for lang, analyzer := range analyzers {
+		terms[lang] = analyzer.Analyze(map[string]string{"en": "Writing", "ru": "Писали", "uk": "Писали"}[lang])
+	}
+
+	// expected
+	assert.Equal(t, map[string][]string{"en": {"write"}, "ru": {"писа"}, "uk": {"пис"}}, terms)
+}
@@ -1,5 +1,9 @@
 // Package lexer provides API for Token Generator.
 //
-// Token Generator returns a Token for each Next iteration.
-// Token is a word or a number.
+// Token Generator returns a Token for each NextToken iteration, Next returns only its text.
+// Token is a word, a number or a symbol with its position and offsets in the text.
+// A smart Lexer created by NewSmartLexer also recognizes emails, URLs, versions, compounds and possessives.
+//
+// Analyzer is a configurable pipeline of a Tokenizer and TokenFilters,
+// analyzers are registered by names, e.g. StandardAnalyzer, to be shared by indexing and querying.
 package lexer
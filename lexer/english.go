@@ -0,0 +1,292 @@
+package lexer
+
+import "strings"
+
+// EnglishAnalyzer is the name of the StandardAnalyzer followed by EnglishStemFilter.
+const EnglishAnalyzer = "english"
+
+// EnglishStemFilter stems tokens by StemEnglish, tokens must be lowercased before it.
+func EnglishStemFilter() TokenFilter {
+	return stemFilter(StemEnglish)
+}
+
+// englishExceptions are words with irregular stems and words which must not be stemmed.
+var englishExceptions = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli", "singly": "singl",
+	"sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas", "cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+// englishStep1aExceptions are words which are kept as is after Step 1a.
+var englishStep1aExceptions = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true,
+	"earring": true, "proceed": true, "exceed": true, "succeed": true,
+}
+
+// englishSuffix is a suffix with its replacement, the rule is applied only when the condition is met.
+type englishSuffix struct {
+	suffix      string
+	replacement string
+	condition   func(w *englishWord, stem int) bool
+}
+
+var englishStep2 = []englishSuffix{
+	{suffix: "ization", replacement: "ize"},
+	{suffix: "ational", replacement: "ate"},
+	{suffix: "fulness", replacement: "ful"},
+	{suffix: "ousness", replacement: "ous"},
+	{suffix: "iveness", replacement: "ive"},
+	{suffix: "tional", replacement: "tion"},
+	{suffix: "biliti", replacement: "ble"},
+	{suffix: "lessli", replacement: "less"},
+	{suffix: "entli", replacement: "ent"},
+	{suffix: "ation", replacement: "ate"},
+	{suffix: "alism", replacement: "al"},
+	{suffix: "aliti", replacement: "al"},
+	{suffix: "ousli", replacement: "ous"},
+	{suffix: "iviti", replacement: "ive"},
+	{suffix: "fulli", replacement: "ful"},
+	{suffix: "enci", replacement: "ence"},
+	{suffix: "anci", replacement: "ance"},
+	{suffix: "abli", replacement: "able"},
+	{suffix: "izer", replacement: "ize"},
+	{suffix: "ator", replacement: "ate"},
+	{suffix: "alli", replacement: "al"},
+	{suffix: "bli", replacement: "ble"},
+	{suffix: "ogi", replacement: "og", condition: func(w *englishWord, stem int) bool {
+		return stem > 0 && w.runes[stem-1] == 'l'
+	}},
+	{suffix: "li", condition: func(w *englishWord, stem int) bool {
+		return stem > 0 && strings.ContainsRune("cdeghkmnrt", w.runes[stem-1])
+	}},
+}
+
+var englishStep3 = []englishSuffix{
+	{suffix: "ational", replacement: "ate"},
+	{suffix: "tional", replacement: "tion"},
+	{suffix: "alize", replacement: "al"},
+	{suffix: "icate", replacement: "ic"},
+	{suffix: "iciti", replacement: "ic"},
+	{suffix: "ative", condition: func(w *englishWord, stem int) bool {
+		return stem >= w.r2
+	}},
+	{suffix: "ical", replacement: "ic"},
+	{suffix: "ness"},
+	{suffix: "ful"},
+}
+
+var englishStep4 = []englishSuffix{
+	{suffix: "ement"}, {suffix: "ance"}, {suffix: "ence"}, {suffix: "able"}, {suffix: "ible"}, {suffix: "ment"},
+	{suffix: "ant"}, {suffix: "ent"}, {suffix: "ism"}, {suffix: "ate"}, {suffix: "iti"}, {suffix: "ous"},
+	{suffix: "ive"}, {suffix: "ize"},
+	{suffix: "ion", condition: func(w *englishWord, stem int) bool {
+		return stem > 0 && (w.runes[stem-1] == 's' || w.runes[stem-1] == 't')
+	}},
+	{suffix: "al"}, {suffix: "er"}, {suffix: "ic"},
+}
+
+// StemEnglish returns the stem of a lowercased English word by the Porter2 (Snowball English) algorithm,
+// e.g. "exampl" for both "example" and "examples". See https://snowballstem.org/algorithms/english/stemmer.html.
+func StemEnglish(word string) string {
+	word = strings.ReplaceAll(word, "’", "'")
+	if len([]rune(word)) <= 2 {
+		return word
+	}
+	word = strings.TrimPrefix(word, "'")
+	if stem, ok := englishExceptions[word]; ok {
+		return stem
+	}
+
+	w := newEnglishWord(word)
+	w.step0()
+	w.step1a()
+	if englishStep1aExceptions[string(w.runes)] {
+		return string(w.runes)
+	}
+	w.step1b()
+	w.step1c()
+	w.replaceLongest(englishStep2, w.r1)
+	w.replaceLongest(englishStep3, w.r1)
+	w.replaceLongest(englishStep4, w.r2)
+	w.step5()
+
+	return strings.ReplaceAll(string(w.runes), "Y", "y")
+}
+
+// englishWord is a word being stemmed, r1 and r2 are starts of its R1 and R2 regions.
+// Consonant y is marked as Y, so it isn't treated as a vowel.
+type englishWord struct {
+	runes  []rune
+	r1, r2 int
+}
+
+func newEnglishWord(word string) *englishWord {
+	w := &englishWord{runes: []rune(word)}
+	for i, r := range w.runes {
+		if r == 'y' && (i == 0 || isEnglishVowel(w.runes[i-1])) {
+			w.runes[i] = 'Y'
+		}
+	}
+
+	w.r1 = w.region(0)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(word, prefix) {
+			w.r1 = len(prefix)
+		}
+	}
+	w.r2 = w.region(w.r1)
+
+	return w
+}
+
+// region returns the start of the region after the first non-vowel following a vowel after start.
+func (w *englishWord) region(start int) int {
+	for i := start + 1; i < len(w.runes); i++ {
+		if !isEnglishVowel(w.runes[i]) && isEnglishVowel(w.runes[i-1]) {
+			return i + 1
+		}
+	}
+
+	return len(w.runes)
+}
+
+func (w *englishWord) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(w.runes), suffix)
+}
+
+// stem returns the start of the suffix.
+func (w *englishWord) stem(suffix string) int {
+	return len(w.runes) - len([]rune(suffix))
+}
+
+func (w *englishWord) replace(suffix, replacement string) {
+	w.runes = append(w.runes[:w.stem(suffix)], []rune(replacement)...)
+}
+
+func (w *englishWord) containsVowel(start, end int) bool {
+	for _, r := range w.runes[start:end] {
+		if isEnglishVowel(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// endsWithShortSyllable checks whether the word ends with a vowel followed by a non-vowel other than w, x or Y
+// and preceded by a non-vowel, or it's a vowel followed by a non-vowel at the beginning of the word.
+func (w *englishWord) endsWithShortSyllable() bool {
+	n := len(w.runes)
+	if n == 2 {
+		return isEnglishVowel(w.runes[0]) && !isEnglishVowel(w.runes[1])
+	}
+
+	return n >= 3 && !isEnglishVowel(w.runes[n-3]) && isEnglishVowel(w.runes[n-2]) &&
+		!isEnglishVowel(w.runes[n-1]) && !strings.ContainsRune("wxY", w.runes[n-1])
+}
+
+func (w *englishWord) isShort() bool {
+	return w.r1 >= len(w.runes) && w.endsWithShortSyllable()
+}
+
+func (w *englishWord) step0() {
+	for _, suffix := range []string{"'s'", "'s", "'"} {
+		if w.hasSuffix(suffix) {
+			w.replace(suffix, "")
+			return
+		}
+	}
+}
+
+func (w *englishWord) step1a() {
+	switch {
+	case w.hasSuffix("sses"):
+		w.replace("sses", "ss")
+	case w.hasSuffix("ied") || w.hasSuffix("ies"):
+		if w.stem("ies") > 1 {
+			w.replace("ies", "i")
+		} else {
+			w.replace("ies", "ie")
+		}
+	case w.hasSuffix("us") || w.hasSuffix("ss"):
+	case w.hasSuffix("s"):
+		if w.containsVowel(0, w.stem("s")-1) {
+			w.replace("s", "")
+		}
+	}
+}
+
+func (w *englishWord) step1b() {
+	for _, suffix := range []string{"eedly", "ingly", "edly", "eed", "ing", "ed"} {
+		if !w.hasSuffix(suffix) {
+			continue
+		}
+
+		if suffix == "eed" || suffix == "eedly" {
+			if w.stem(suffix) >= w.r1 {
+				w.replace(suffix, "ee")
+			}
+			return
+		}
+
+		if !w.containsVowel(0, w.stem(suffix)) {
+			return
+		}
+		w.replace(suffix, "")
+		switch n := len(w.runes); {
+		case w.hasSuffix("at") || w.hasSuffix("bl") || w.hasSuffix("iz"):
+			w.runes = append(w.runes, 'e')
+		case n >= 2 && w.runes[n-1] == w.runes[n-2] && strings.ContainsRune("bdfgmnprt", w.runes[n-1]):
+			w.runes = w.runes[:n-1]
+		case w.isShort():
+			w.runes = append(w.runes, 'e')
+		}
+		return
+	}
+}
+
+func (w *englishWord) step1c() {
+	n := len(w.runes)
+	if n > 2 && (w.runes[n-1] == 'y' || w.runes[n-1] == 'Y') && !isEnglishVowel(w.runes[n-2]) {
+		w.runes[n-1] = 'i'
+	}
+}
+
+// replaceLongest replaces the longest of suffixes when it's in the region and its condition is met.
+// Shorter suffixes aren't tried when the condition of the longest one isn't met.
+func (w *englishWord) replaceLongest(suffixes []englishSuffix, region int) {
+	for _, s := range suffixes {
+		if !w.hasSuffix(s.suffix) {
+			continue
+		}
+
+		stem := w.stem(s.suffix)
+		if stem >= region && (s.condition == nil || s.condition(w, stem)) {
+			w.replace(s.suffix, s.replacement)
+		}
+		return
+	}
+}
+
+func (w *englishWord) step5() {
+	n := len(w.runes)
+	switch {
+	case n > 0 && w.runes[n-1] == 'e':
+		if n-1 >= w.r2 {
+			w.runes = w.runes[:n-1]
+			return
+		}
+		if n-1 >= w.r1 {
+			w.runes = w.runes[:n-1]
+			if w.endsWithShortSyllable() {
+				w.runes = append(w.runes, 'e')
+			}
+		}
+	case n > 1 && w.runes[n-1] == 'l' && n-1 >= w.r2 && w.runes[n-2] == 'l':
+		w.runes = w.runes[:n-1]
+	}
+}
+
+func isEnglishVowel(r rune) bool {
+	return strings.ContainsRune("aeiouy", r)
+}
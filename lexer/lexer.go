@@ -1,25 +1,120 @@
 package lexer
 
 import (
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
+// TokenType represents a kind of Token.
+type TokenType int
+
+const (
+	// WordToken is a run of letters and digits starting with a letter.
+	WordToken TokenType = iota
+	// NumberToken is a run of digits.
+	NumberToken
+	// SymbolToken is a single character which is neither a letter, a digit, a space nor a punctuation mark, e.g. "$".
+	SymbolToken
+	// CJKToken is a bigram of adjacent Han, Hiragana, Katakana or Hangul characters,
+	// or a single character which has no CJK neighbours.
+	CJKToken
+
+	// Types below are returned only by a smart Lexer, see NewSmartLexer.
+
+	// EmailToken is an email address, e.g. "gopher@go.dev".
+	EmailToken
+	// URLToken is a URL with a scheme or starting with "www.", e.g. "https://go.dev/learn/".
+	URLToken
+	// DecimalToken is a number with a decimal separator, e.g. "3.14" or "0,5".
+	DecimalToken
+	// VersionToken is a number of three or more dot separated parts or a number prefixed with "v", e.g. "1.22.0" or "v1.2".
+	VersionToken
+	// HyphenatedToken is a whole hyphenated compound, e.g. "well-known" or "1564-1616", its parts follow it.
+	HyphenatedToken
+	// PossessiveToken is an English possessive without "'s", e.g. "shakespeare" for "Shakespeare's".
+	PossessiveToken
+
+	// SynonymToken is a word of a synonym added by SynonymFilter.
+	SynonymToken
+)
+
+// String returns a string representation of the TokenType.
+func (t TokenType) String() string {
+	switch t {
+	case WordToken:
+		return "Word"
+	case NumberToken:
+		return "Number"
+	case SymbolToken:
+		return "Symbol"
+	case CJKToken:
+		return "CJK"
+	case EmailToken:
+		return "Email"
+	case URLToken:
+		return "URL"
+	case DecimalToken:
+		return "Decimal"
+	case VersionToken:
+		return "Version"
+	case HyphenatedToken:
+		return "Hyphenated"
+	case PossessiveToken:
+		return "Possessive"
+	case SynonymToken:
+		return "Synonym"
+	}
+
+	return "Invalid(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Token is a term of a text with its place in the text.
+type Token struct {
+	Text string
+	Type TokenType
+	// Position is the index of the token in the token stream, it isn't changed when filters remove tokens,
+	// so gaps of removed tokens are kept for phrase and proximity matching.
+	Position int
+	// Start and End are byte offsets of the token in the text, End is exclusive.
+	Start, End int
+	// StartRune and EndRune are rune offsets of the token in the text, EndRune is exclusive.
+	StartRune, EndRune int
+}
+
 type Lexer struct {
 	Terms []rune
+
+	// text is the source of Terms, offset and runeOffset are byte and rune offsets of Terms in it.
+	text               string
+	offset, runeOffset int
+	position           int
+	// pending are tokens of the last CJK run or hyphenated compound which aren't returned yet.
+	pending []Token
+	// smart turns on recognition of emails, URLs, decimals, versions, compounds and possessives.
+	smart bool
 }
 
+// NewLexer creates a Lexer of the content joined together, offsets of tokens are offsets in the joined text.
 func NewLexer(content ...string) *Lexer {
-	t := make([]rune, 0)
-	for _, word := range content {
-		t = append(t, []rune(word)...)
-	}
-	return &Lexer{Terms: t}
+	text := strings.Join(content, "")
+
+	return &Lexer{Terms: []rune(text), text: text}
 }
 
 func (l *Lexer) chop(n int) []rune {
 	term := l.Terms[:n]
 	l.Terms = l.Terms[n:]
+	for _, r := range term {
+		// Terms of a Lexer made by a struct literal have no text, their runes are counted as UTF-8.
+		size := utf8.RuneLen(r)
+		if l.offset < len(l.text) {
+			_, size = utf8.DecodeRuneInString(l.text[l.offset:])
+		}
+		l.offset += size
+	}
+	l.runeOffset += n
 
 	return term
 }
@@ -33,10 +128,11 @@ func (l *Lexer) chopWhile(predicate func(component rune) bool) []rune {
 	return l.chop(n)
 }
 
+// trimLeft chops spaces and punctuation marks before a token, a smart Lexer chops symbols as well.
 func (l *Lexer) trimLeft() {
-	for len(l.Terms) != 0 && (unicode.IsSpace(l.Terms[0]) || unicode.IsPunct(l.Terms[0])) {
-		l.Terms = l.Terms[1:]
-	}
+	l.chopWhile(func(c rune) bool {
+		return unicode.IsSpace(c) || unicode.IsPunct(c) || (l.smart && !unicode.IsLetter(c) && !unicode.IsNumber(c))
+	})
 }
 
 // All returns all tokens
@@ -49,24 +145,108 @@ func (l *Lexer) All() []string {
 	return tokens
 }
 
+// Next returns the next lowercased token, empty string is returned when there are no tokens left.
 func (l *Lexer) Next() string {
+	token, _ := l.NextToken()
+
+	return token.Text
+}
+
+// Tokens returns all tokens left.
+func (l *Lexer) Tokens() []Token {
+	tokens := make([]Token, 0)
+	for token, ok := l.NextToken(); ok; token, ok = l.NextToken() {
+		tokens = append(tokens, token)
+	}
+
+	return tokens
+}
+
+// NextToken returns the next Token with lowercased Text, false is returned when there are no tokens left.
+// The text as it's written in the source is the source[Start:End].
+func (l *Lexer) NextToken() (Token, bool) {
+	token, ok := l.next()
+	token.Text = strings.ToLower(token.Text)
+
+	return token, ok
+}
+
+// next returns the next token as it's written in the text.
+func (l *Lexer) next() (Token, bool) {
+	if len(l.pending) != 0 {
+		token := l.pending[0]
+		l.pending = l.pending[1:]
+
+		return token, true
+	}
+
 	l.trimLeft()
 
 	if len(l.Terms) == 0 {
-		return ""
+		return Token{}, false
+	}
+
+	if isCJK(l.Terms[0]) {
+		l.pending = l.chopCJK()
+
+		return l.next()
 	}
 
-	if unicode.IsNumber(l.Terms[0]) {
-		return strings.ToLower(string(l.chopWhile(unicode.IsNumber)))
+	if l.smart {
+		return l.nextSmart()
 	}
 
-	if unicode.IsLetter(l.Terms[0]) {
-		token := l.chopWhile(func(c rune) bool {
-			return unicode.IsNumber(c) || unicode.IsLetter(c)
-		})
+	token := Token{Position: l.position, Start: l.offset, StartRune: l.runeOffset}
+	switch {
+	case unicode.IsNumber(l.Terms[0]):
+		token.Text, token.Type = string(l.chopWhile(unicode.IsNumber)), NumberToken
+	case unicode.IsLetter(l.Terms[0]):
+		token.Text, token.Type = string(l.chopWhile(func(c rune) bool {
+			return (unicode.IsNumber(c) || unicode.IsLetter(c)) && !isCJK(c)
+		})), WordToken
+	default:
+		token.Text, token.Type = string(l.chop(1)), SymbolToken
+	}
+	token.End, token.EndRune = l.offset, l.runeOffset
+	l.position++
+
+	return token, true
+}
 
-		return strings.ToLower(string(token))
+// chopCJK chops a run of CJK characters and returns its overlapping bigrams, e.g. "東京" and "京都" for "東京都".
+// Words of these scripts aren't separated by spaces, so bigrams make them searchable without a dictionary.
+func (l *Lexer) chopCJK() []Token {
+	type offset struct{ bytes, runes int }
+	run := l.Terms
+	offsets := make([]offset, 0)
+	for len(l.Terms) != 0 && isCJK(l.Terms[0]) {
+		offsets = append(offsets, offset{l.offset, l.runeOffset})
+		l.chop(1)
 	}
+	offsets = append(offsets, offset{l.offset, l.runeOffset})
+
+	n := max(len(offsets)-2, 1)
+	tokens := make([]Token, n)
+	for i := range tokens {
+		start, end := offsets[i], offsets[min(i+2, len(offsets)-1)]
+		tokens[i] = Token{
+			Text:      string(run[start.runes-offsets[0].runes : end.runes-offsets[0].runes]),
+			Type:      CJKToken,
+			Position:  l.position,
+			Start:     start.bytes,
+			End:       end.bytes,
+			StartRune: start.runes,
+			EndRune:   end.runes,
+		}
+		l.position++
+	}
+
+	return tokens
+}
 
-	return string(l.chop(1))
+// isCJK checks whether the rune is a Han, Hiragana, Katakana or Hangul character,
+// including the Katakana prolonged sound mark which belongs to the Common script.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		r == 'ー' || r == 'ｰ'
 }
@@ -0,0 +1,61 @@
+package lexer_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLexer_CJK(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{name: "should split Han text into overlapping bigrams", text: "東京都", expected: []string{"東京", "京都"}},
+		{name: "should return a single character as a unigram", text: "我 爱 Go", expected: []string{"我", "爱", "go"}},
+		{name: "should split Chinese text", text: "搜索引擎", expected: []string{"搜索", "索引", "引擎"}},
+		{name: "should bigram Japanese scripts together", text: "検索エンジンです", expected: []string{"検索", "索エ", "エン", "ンジ", "ジン", "ンで", "です"}},
+		{name: "should keep the prolonged sound mark in Katakana", text: "コーヒー", expected: []string{"コー", "ーヒ", "ヒー"}},
+		{name: "should split Hangul words", text: "한국어 검색", expected: []string{"한국", "국어", "검색"}},
+		{name: "should split mixed Latin and CJK text", text: "Go言語入門 v2", expected: []string{"go", "言語", "語入", "入門", "v2"}},
+		{name: "should split CJK text with numbers", text: "2024年12月", expected: []string{"2024", "年", "12", "月"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			terms := lexer.NewLexer(tt.text).All()
+
+			// expected
+			assert.Equal(t, tt.expected, terms)
+		})
+	}
+
+	t.Run("should return positions and offsets of bigrams", func(t *testing.T) {
+		// when
+		tokens := lexer.NewLexer("Go 東京都").Tokens()
+
+		// expected
+		assert.Equal(t, []lexer.Token{
+			{Text: "go", Type: lexer.WordToken, Position: 0, Start: 0, End: 2, StartRune: 0, EndRune: 2},
+			{Text: "東京", Type: lexer.CJKToken, Position: 1, Start: 3, End: 9, StartRune: 3, EndRune: 5},
+			{Text: "京都", Type: lexer.CJKToken, Position: 2, Start: 6, End: 12, StartRune: 4, EndRune: 6},
+		}, tokens)
+	})
+
+	t.Run("should split CJK text of a Lexer made by a struct literal", func(t *testing.T) {
+		// given
+		l := &lexer.Lexer{Terms: []rune("東京都 hello")}
+
+		// when
+		tokens := l.Tokens()
+
+		// expected
+		assert.Equal(t, []lexer.Token{
+			{Text: "東京", Type: lexer.CJKToken, Position: 0, Start: 0, End: 6, StartRune: 0, EndRune: 2},
+			{Text: "京都", Type: lexer.CJKToken, Position: 1, Start: 3, End: 9, StartRune: 1, EndRune: 3},
+			{Text: "hello", Type: lexer.WordToken, Position: 2, Start: 10, End: 15, StartRune: 4, EndRune: 9},
+		}, tokens)
+	})
+}
@@ -0,0 +1,128 @@
+package lexer_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLexer_NextToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []lexer.Token
+	}{
+		{
+			name: "should return tokens with positions and offsets",
+			text: "Go 1.22, $5",
+			expected: []lexer.Token{
+				{Text: "go", Type: lexer.WordToken, Position: 0, Start: 0, End: 2, StartRune: 0, EndRune: 2},
+				{Text: "1", Type: lexer.NumberToken, Position: 1, Start: 3, End: 4, StartRune: 3, EndRune: 4},
+				{Text: "22", Type: lexer.NumberToken, Position: 2, Start: 5, End: 7, StartRune: 5, EndRune: 7},
+				{Text: "$", Type: lexer.SymbolToken, Position: 3, Start: 9, End: 10, StartRune: 9, EndRune: 10},
+				{Text: "5", Type: lexer.NumberToken, Position: 4, Start: 10, End: 11, StartRune: 10, EndRune: 11},
+			},
+		},
+		{
+			name: "should count bytes and runes of multibyte text separately",
+			text: "Привіт, Go!",
+			expected: []lexer.Token{
+				{Text: "привіт", Type: lexer.WordToken, Position: 0, Start: 0, End: 12, StartRune: 0, EndRune: 6},
+				{Text: "go", Type: lexer.WordToken, Position: 1, Start: 14, End: 16, StartRune: 8, EndRune: 10},
+			},
+		},
+		{
+			name: "should count invalid bytes as single bytes",
+			text: "a\xffb c",
+			expected: []lexer.Token{
+				{Text: "a", Type: lexer.WordToken, Position: 0, Start: 0, End: 1, StartRune: 0, EndRune: 1},
+				{Text: "�", Type: lexer.SymbolToken, Position: 1, Start: 1, End: 2, StartRune: 1, EndRune: 2},
+				{Text: "b", Type: lexer.WordToken, Position: 2, Start: 2, End: 3, StartRune: 2, EndRune: 3},
+				{Text: "c", Type: lexer.WordToken, Position: 3, Start: 4, End: 5, StartRune: 4, EndRune: 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			l := lexer.NewLexer(tt.text)
+
+			// when
+			tokens := l.Tokens()
+
+			// expected
+			assert.Equal(t, tt.expected, tokens)
+		})
+	}
+
+	t.Run("should keep Next and All working", func(t *testing.T) {
+		// given
+		text := "Hello, it is Misha. Why are you here...?"
+		tokens := lexer.NewLexer(text).Tokens()
+
+		// when
+		all := lexer.NewLexer(text).All()
+
+		// expected
+		require.Len(t, all, len(tokens))
+		for i, token := range tokens {
+			assert.Equal(t, token.Text, all[i])
+		}
+		l := lexer.NewLexer(text)
+		assert.Equal(t, "hello", l.Next())
+		token, ok := l.NextToken()
+		assert.True(t, ok)
+		assert.Equal(t, "it", token.Text)
+		assert.Equal(t, 1, token.Position)
+	})
+
+	t.Run("should return false when there are no tokens left", func(t *testing.T) {
+		// given
+		l := lexer.NewLexer(" ... ")
+
+		// when
+		_, ok := l.NextToken()
+
+		// expected
+		assert.False(t, ok)
+		assert.Empty(t, l.Next())
+	})
+}
+
+func TestAnalyzer_Tokens(t *testing.T) {
+	t.Run("should keep positions and offsets of tokens through filters", func(t *testing.T) {
+		// given
+		words, err := lexer.Stopwords("en")
+		require.NoError(t, err)
+		english, err := lexer.LookupAnalyzer(lexer.EnglishAnalyzer)
+		require.NoError(t, err)
+		text := "The Art of War"
+
+		// when
+		tokens := english.Prepend(lexer.StopFilter(words)).Tokens(text)
+
+		// expected
+		require.Len(t, tokens, 2)
+		assert.Equal(t, lexer.Token{Text: "art", Position: 1, Start: 4, End: 7, StartRune: 4, EndRune: 7}, tokens[0])
+		assert.Equal(t, lexer.Token{Text: "war", Position: 3, Start: 11, End: 14, StartRune: 11, EndRune: 14}, tokens[1])
+		assert.Equal(t, "War", text[tokens[1].Start:tokens[1].End])
+	})
+
+	t.Run("should return offsets of whitespace and keyword tokens", func(t *testing.T) {
+		// given
+		whitespace := lexer.NewAnalyzer(lexer.WhitespaceTokenizer())
+		keyword := lexer.NewAnalyzer(lexer.KeywordTokenizer())
+
+		// when
+		words, all := whitespace.Tokens(" ґанок  go.mod"), keyword.Tokens(" ґанок  go.mod ")
+
+		// expected
+		assert.Equal(t, []lexer.Token{
+			{Text: "ґанок", Position: 0, Start: 1, End: 11, StartRune: 1, EndRune: 6},
+			{Text: "go.mod", Position: 1, Start: 13, End: 19, StartRune: 8, EndRune: 14},
+		}, words)
+		assert.Equal(t, []lexer.Token{{Text: "ґанок  go.mod", Start: 1, End: 19, StartRune: 1, EndRune: 14}}, all)
+	})
+}
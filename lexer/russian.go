@@ -0,0 +1,151 @@
+package lexer
+
+import "strings"
+
+// RussianAnalyzer is the name of the StandardAnalyzer followed by RussianStemFilter.
+const RussianAnalyzer = "russian"
+
+// RussianStemFilter stems tokens by StemRussian, tokens must be lowercased before it.
+func RussianStemFilter() TokenFilter {
+	return stemFilter(StemRussian)
+}
+
+// Suffix groups of the Snowball Russian stemmer, groups named "after" are removed only after а or я.
+var (
+	russianGerundAfter   = []string{"вшись", "вши", "в"}
+	russianGerund        = []string{"ившись", "ывшись", "ивши", "ывши", "ив", "ыв"}
+	russianReflexive     = []string{"ся", "сь"}
+	russianAdjective     = []string{"ими", "ыми", "его", "ого", "ему", "ому", "ее", "ие", "ые", "ое", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею"}
+	russianParticleAfter = []string{"ем", "нн", "вш", "ющ", "щ"}
+	russianParticle      = []string{"ивш", "ывш", "ующ"}
+	russianVerbAfter     = []string{"ете", "йте", "ешь", "нно", "ла", "на", "ли", "ем", "ло", "но", "ет", "ют", "ны", "ть", "й", "л", "н"}
+	russianVerb          = []string{"ейте", "уйте", "ила", "ыла", "ена", "ите", "или", "ыли", "ило", "ыло", "ено", "ует", "уют", "ены", "ить", "ыть", "ишь", "ей", "уй", "ил", "ыл", "им", "ым", "ен", "ят", "ит", "ыт", "ую", "ю"}
+	russianNoun          = []string{"иями", "ями", "ами", "ией", "иям", "ием", "иях", "ев", "ов", "ие", "ье", "еи", "ии", "ей", "ой", "ий", "ям", "ем", "ам", "ом", "ах", "ях", "ию", "ью", "ия", "ья", "а", "е", "и", "й", "о", "у", "ы", "ь", "ю", "я"}
+	russianSuperlative   = []string{"ейше", "ейш"}
+	russianDerivational  = []string{"ость", "ост"}
+)
+
+// StemRussian returns the stem of a lowercased Russian word by the Snowball Russian algorithm,
+// e.g. "важн" for "важная" and "важнейшими". See https://snowballstem.org/algorithms/russian/stemmer.html.
+func StemRussian(word string) string {
+	w := newSlavicWord(strings.ReplaceAll(word, "ё", "е"), "аеиоуыэюя")
+
+	// Step 1: a perfective gerund, otherwise a reflexive ending followed by an adjectival, verb or noun ending.
+	if !w.removeAfter(russianGerundAfter, w.rv, "ая") && !w.remove(russianGerund, w.rv) {
+		w.remove(russianReflexive, w.rv)
+		if w.remove(russianAdjective, w.rv) {
+			_ = w.removeAfter(russianParticleAfter, w.rv, "ая") || w.remove(russianParticle, w.rv)
+		} else if !w.removeAfter(russianVerbAfter, w.rv, "ая") && !w.remove(russianVerb, w.rv) {
+			w.remove(russianNoun, w.rv)
+		}
+	}
+
+	// Step 2.
+	w.remove([]string{"и"}, w.rv)
+
+	// Step 3.
+	w.remove(russianDerivational, w.r2)
+
+	// Step 4.
+	switch {
+	case w.remove(russianSuperlative, w.rv):
+		w.undouble('н')
+	case w.hasSuffix("нн", w.rv):
+		w.undouble('н')
+	default:
+		w.remove([]string{"ь"}, w.rv)
+	}
+
+	return string(w.runes)
+}
+
+// slavicWord is a word being stemmed by Russian or Ukrainian stemmers.
+// rv is the start of the region after the first vowel, r2 is the start of the R2 region.
+type slavicWord struct {
+	runes  []rune
+	vowels string
+	rv, r2 int
+}
+
+func newSlavicWord(word, vowels string) *slavicWord {
+	w := &slavicWord{runes: []rune(word), vowels: vowels}
+	w.rv = w.pastVowel(0)
+	r1 := w.pastNonVowel(w.rv)
+	w.r2 = w.pastNonVowel(w.pastVowel(r1))
+
+	return w
+}
+
+func (w *slavicWord) isVowel(r rune) bool {
+	return strings.ContainsRune(w.vowels, r)
+}
+
+// pastVowel returns the position after the first vowel starting from i.
+func (w *slavicWord) pastVowel(i int) int {
+	for ; i < len(w.runes); i++ {
+		if w.isVowel(w.runes[i]) {
+			return i + 1
+		}
+	}
+
+	return len(w.runes)
+}
+
+// pastNonVowel returns the position after the first non-vowel starting from i.
+func (w *slavicWord) pastNonVowel(i int) int {
+	for ; i < len(w.runes); i++ {
+		if !w.isVowel(w.runes[i]) {
+			return i + 1
+		}
+	}
+
+	return len(w.runes)
+}
+
+// hasSuffix checks whether the word ends with the suffix which is entirely in the region.
+func (w *slavicWord) hasSuffix(suffix string, region int) bool {
+	stem := len(w.runes) - len([]rune(suffix))
+
+	return stem >= region && strings.HasSuffix(string(w.runes), suffix)
+}
+
+// longest returns the longest of suffixes in the region, suffixes must be sorted by length in descending order.
+func (w *slavicWord) longest(suffixes []string, region int) (string, bool) {
+	for _, suffix := range suffixes {
+		if w.hasSuffix(suffix, region) {
+			return suffix, true
+		}
+	}
+
+	return "", false
+}
+
+// remove removes the longest of suffixes in the region.
+func (w *slavicWord) remove(suffixes []string, region int) bool {
+	suffix, ok := w.longest(suffixes, region)
+	if ok {
+		w.runes = w.runes[:len(w.runes)-len([]rune(suffix))]
+	}
+
+	return ok
+}
+
+// removeAfter removes the longest of suffixes in the region when it follows one of letters in the region.
+// Shorter suffixes aren't tried when the longest one doesn't follow the letters.
+func (w *slavicWord) removeAfter(suffixes []string, region int, letters string) bool {
+	suffix, ok := w.longest(suffixes, region)
+	stem := len(w.runes) - len([]rune(suffix))
+	if !ok || stem <= region || !strings.ContainsRune(letters, w.runes[stem-1]) {
+		return false
+	}
+	w.runes = w.runes[:stem]
+
+	return true
+}
+
+// undouble removes the last letter when the word ends with it twice.
+func (w *slavicWord) undouble(letter rune) {
+	if n := len(w.runes); n >= 2 && w.runes[n-1] == letter && w.runes[n-2] == letter {
+		w.runes = w.runes[:n-1]
+	}
+}
@@ -0,0 +1,155 @@
+package lexer
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	urlRe    = regexp.MustCompile(`^(?:[a-zA-Z][a-zA-Z0-9+.-]*://|www\.)[^\s<>"'` + "`" + `]+`)
+	emailRe  = regexp.MustCompile(`^[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)*\.\p{L}{2,}`)
+	numberRe = regexp.MustCompile(`^(?:[vV]\p{Nd}+(?:\.\p{Nd}+)+|\p{Nd}+(?:[.,]\p{Nd}+)+)`)
+)
+
+// NewSmartLexer creates a Lexer which keeps emails, URLs, decimal and version numbers as single tokens,
+// returns hyphenated compounds both as a whole and by parts, removes "'s" of English possessives
+// and skips symbols, e.g. "$". Every Token is tagged with its TokenType.
+func NewSmartLexer(content ...string) *Lexer {
+	l := NewLexer(content...)
+	l.smart = true
+
+	return l
+}
+
+// SmartTokenizer splits text like NewSmartLexer, but keeps the case of tokens.
+func SmartTokenizer() Tokenizer {
+	return TokenizerFunc(func(text string) []Token {
+		l := NewSmartLexer(text)
+		tokens := make([]Token, 0)
+		for token, ok := l.next(); ok; token, ok = l.next() {
+			tokens = append(tokens, token)
+		}
+
+		return tokens
+	})
+}
+
+// nextSmart returns the next token of a smart Lexer, Terms must start with a letter or a digit.
+func (l *Lexer) nextSmart() (Token, bool) {
+	rest := l.text[l.offset:]
+	if match := urlRe.FindString(rest); match != "" {
+		return l.chopToken(strings.TrimRight(match, ".,;:!?)]}"), URLToken), true
+	}
+	if match := emailRe.FindString(rest); match != "" {
+		return l.chopToken(match, EmailToken), true
+	}
+	if match := numberRe.FindString(rest); match != "" && !l.continuesWord(utf8.RuneCountInString(match)) {
+		return l.chopToken(match, numberType(match)), true
+	}
+
+	l.pending = l.chopCompound()
+
+	return l.next()
+}
+
+// continuesWord checks whether a letter or a hyphen follows the first n runes, e.g. "2.0-beta" or "1.5x".
+func (l *Lexer) continuesWord(n int) bool {
+	return n < len(l.Terms) && (unicode.IsLetter(l.Terms[n]) || l.Terms[n] == '-')
+}
+
+// chopToken chops the match from the start of Terms.
+func (l *Lexer) chopToken(match string, tokenType TokenType) Token {
+	token := Token{Type: tokenType, Position: l.position, Start: l.offset, StartRune: l.runeOffset}
+	token.Text = string(l.chop(utf8.RuneCountInString(match)))
+	token.End, token.EndRune = l.offset, l.runeOffset
+	l.position++
+
+	return token
+}
+
+func numberType(number string) TokenType {
+	switch separators := strings.Count(number, ".") + strings.Count(number, ","); {
+	case strings.HasPrefix(number, "v") || strings.HasPrefix(number, "V"):
+		return VersionToken
+	case separators == 1:
+		return DecimalToken
+	case !strings.Contains(number, ","):
+		return VersionToken
+	default:
+		return NumberToken
+	}
+}
+
+// chopCompound chops a word or a number, or a hyphenated compound of them.
+// A compound is returned as a HyphenatedToken followed by its parts, the first part has the position of the compound.
+// Text of the compound is made of texts of its parts, so "'s" is removed from every part, e.g. "co-op-x" for "co-op's-x".
+func (l *Lexer) chopCompound() []Token {
+	parts := []Token{l.chopPart()}
+	for len(l.Terms) >= 2 && l.Terms[0] == '-' && isWordRune(l.Terms[1]) {
+		l.chop(1)
+		part := l.chopPart()
+		part.Position = parts[len(parts)-1].Position + 1
+		parts = append(parts, part)
+	}
+	l.position = parts[len(parts)-1].Position + 1
+
+	if len(parts) == 1 {
+		return parts
+	}
+
+	texts := make([]string, len(parts))
+	for i, part := range parts {
+		texts[i] = part.Text
+	}
+	first, last := parts[0], parts[len(parts)-1]
+	whole := Token{
+		Text:      strings.Join(texts, "-"),
+		Type:      HyphenatedToken,
+		Position:  first.Position,
+		Start:     first.Start,
+		End:       last.End,
+		StartRune: first.StartRune,
+		EndRune:   last.EndRune,
+	}
+
+	return append([]Token{whole}, parts...)
+}
+
+// chopPart chops letters and digits with apostrophes between letters, e.g. "don't".
+// A trailing "'s" is removed from the token text, but it's kept in offsets.
+func (l *Lexer) chopPart() Token {
+	token := Token{Type: NumberToken, Position: l.position, Start: l.offset, StartRune: l.runeOffset}
+	n := 0
+	for n < len(l.Terms) {
+		switch r := l.Terms[n]; {
+		case isWordRune(r):
+			if unicode.IsLetter(r) {
+				token.Type = WordToken
+			}
+			n++
+			continue
+		case isApostrophe(r) && n > 0 && unicode.IsLetter(l.Terms[n-1]) && n+1 < len(l.Terms) && unicode.IsLetter(l.Terms[n+1]) && !isCJK(l.Terms[n+1]):
+			n++
+			continue
+		}
+		break
+	}
+
+	token.Text = string(l.chop(n))
+	token.End, token.EndRune = l.offset, l.runeOffset
+	if runes := []rune(token.Text); len(runes) > 2 && isApostrophe(runes[len(runes)-2]) && unicode.ToLower(runes[len(runes)-1]) == 's' {
+		token.Text, token.Type = string(runes[:len(runes)-2]), PossessiveToken
+	}
+
+	return token
+}
+
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsNumber(r)) && !isCJK(r)
+}
+
+func isApostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
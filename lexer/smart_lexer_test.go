@@ -0,0 +1,127 @@
+package lexer_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type typedToken struct {
+	Text string
+	Type lexer.TokenType
+}
+
+func TestNewSmartLexer(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []typedToken
+	}{
+		{
+			name: "should remove possessive endings",
+			text: "Shakespeare's sonnets and Lucrece’s story",
+			expected: []typedToken{
+				{"shakespeare", lexer.PossessiveToken}, {"sonnets", lexer.WordToken}, {"and", lexer.WordToken},
+				{"lucrece", lexer.PossessiveToken}, {"story", lexer.WordToken},
+			},
+		},
+		{
+			name:     "should keep contractions",
+			text:     "don't o'clock",
+			expected: []typedToken{{"don't", lexer.WordToken}, {"o'clock", lexer.WordToken}},
+		},
+		{
+			name: "should keep decimal and version numbers",
+			text: "Go 1.22.0 is 3.5 times faster than 1,000,000 ops, in 2024.",
+			expected: []typedToken{
+				{"go", lexer.WordToken}, {"1.22.0", lexer.VersionToken}, {"is", lexer.WordToken},
+				{"3.5", lexer.DecimalToken}, {"times", lexer.WordToken}, {"faster", lexer.WordToken},
+				{"than", lexer.WordToken}, {"1,000,000", lexer.NumberToken}, {"ops", lexer.WordToken},
+				{"in", lexer.WordToken}, {"2024", lexer.NumberToken},
+			},
+		},
+		{
+			name: "should return hyphenated compounds and their parts",
+			text: "In Shakespeare's era (1564-1616) a well-known mother-in-law's play",
+			expected: []typedToken{
+				{"in", lexer.WordToken}, {"shakespeare", lexer.PossessiveToken}, {"era", lexer.WordToken},
+				{"1564-1616", lexer.HyphenatedToken}, {"1564", lexer.NumberToken}, {"1616", lexer.NumberToken},
+				{"a", lexer.WordToken},
+				{"well-known", lexer.HyphenatedToken}, {"well", lexer.WordToken}, {"known", lexer.WordToken},
+				{"mother-in-law", lexer.HyphenatedToken}, {"mother", lexer.WordToken}, {"in", lexer.WordToken},
+				{"law", lexer.PossessiveToken}, {"play", lexer.WordToken},
+			},
+		},
+		{
+			name: "should remove possessives inside compounds",
+			text: "co-op's-x CO-OP's",
+			expected: []typedToken{
+				{"co-op-x", lexer.HyphenatedToken}, {"co", lexer.WordToken}, {"op", lexer.PossessiveToken}, {"x", lexer.WordToken},
+				{"co-op", lexer.HyphenatedToken}, {"co", lexer.WordToken}, {"op", lexer.PossessiveToken},
+			},
+		},
+		{
+			name: "should keep emails and URLs",
+			text: "Write to gopher@go.dev or see https://go.dev/learn/?q=1, (www.golang.org).",
+			expected: []typedToken{
+				{"write", lexer.WordToken}, {"to", lexer.WordToken}, {"gopher@go.dev", lexer.EmailToken},
+				{"or", lexer.WordToken}, {"see", lexer.WordToken}, {"https://go.dev/learn/?q=1", lexer.URLToken},
+				{"www.golang.org", lexer.URLToken},
+			},
+		},
+		{
+			name:     "should skip stray symbols",
+			text:     "echo $$ > /sys/fs & costs $5 + 10%",
+			expected: []typedToken{{"echo", lexer.WordToken}, {"sys", lexer.WordToken}, {"fs", lexer.WordToken}, {"costs", lexer.WordToken}, {"5", lexer.NumberToken}, {"10", lexer.NumberToken}},
+		},
+		{
+			name:     "should split CJK text into bigrams",
+			text:     "Go言語 v1.2",
+			expected: []typedToken{{"go", lexer.WordToken}, {"言語", lexer.CJKToken}, {"v1.2", lexer.VersionToken}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			l := lexer.NewSmartLexer(tt.text)
+
+			// when
+			tokens := l.Tokens()
+
+			// expected
+			actual := make([]typedToken, len(tokens))
+			for i, token := range tokens {
+				actual[i] = typedToken{token.Text, token.Type}
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	t.Run("should return positions and offsets of compounds and their parts", func(t *testing.T) {
+		// given
+		text := "well-known Shakespeare's"
+
+		// when
+		tokens := lexer.NewSmartLexer(text).Tokens()
+
+		// expected
+		require.Len(t, tokens, 4)
+		assert.Equal(t, []int{0, 0, 1, 2}, []int{tokens[0].Position, tokens[1].Position, tokens[2].Position, tokens[3].Position})
+		assert.Equal(t, "well-known", text[tokens[0].Start:tokens[0].End])
+		assert.Equal(t, "known", text[tokens[2].Start:tokens[2].End])
+		assert.Equal(t, "Shakespeare's", text[tokens[3].Start:tokens[3].End])
+	})
+
+	t.Run("should not stem emails, URLs and numbers", func(t *testing.T) {
+		// given
+		analyzer := lexer.NewAnalyzer(lexer.SmartTokenizer(), lexer.LowercaseFilter(), lexer.EnglishStemFilter())
+
+		// when
+		terms := analyzer.Analyze("Writing to users@go.dev about Shakespeare's examples 1.22.0")
+
+		// expected
+		assert.Equal(t, []string{"write", "to", "users@go.dev", "about", "shakespear", "exampl", "1.22.0"}, terms)
+	})
+}
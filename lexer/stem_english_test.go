@@ -0,0 +1,98 @@
+package lexer_test
+
+import (
+	"bufio"
+	"errors"
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"os"
+	"path"
+	"testing"
+)
+
+// englishStems are words checked by hand against the Porter2 steps, including forms with apostrophes
+// which the Snowball vocabulary doesn't have.
+var englishStems = map[string]string{
+	"examples": "exampl", "example": "exampl", "writing": "write", "write": "write",
+	"caresses": "caress", "ponies": "poni", "ties": "tie", "cries": "cri", "cats": "cat", "gas": "gas",
+	"gaps": "gap", "kiwis": "kiwi", "this": "this", "focus": "focus", "agreed": "agre", "hopping": "hop",
+	"hoping": "hope", "running": "run", "luxuriated": "luxuri", "enjoyed": "enjoy", "playing": "play",
+	"cry": "cri", "by": "by", "say": "say", "happy": "happi", "generously": "generous",
+	"relational": "relat", "conditional": "condit", "rational": "ration", "digitizer": "digit",
+	"operator": "oper", "feudalism": "feudal", "hopefulness": "hope", "formality": "formal",
+	"sensitivity": "sensit", "sensibility": "sensibl", "triplicate": "triplic", "formative": "format",
+	"controllable": "control", "generate": "generat", "generation": "generat", "communism": "communism",
+	"communication": "communic", "arsenal": "arsenal", "skies": "sky", "dying": "die", "news": "news",
+	"succeeding": "succeed", "proceed": "proceed", "inning": "inning", "shakespeare's": "shakespear",
+	"shakespeare’s": "shakespear", "'tis": "tis",
+}
+
+func TestStemEnglish(t *testing.T) {
+	for word, expected := range englishStems {
+		t.Run(word, func(t *testing.T) {
+			// when
+			stem := lexer.StemEnglish(word)
+
+			// expected
+			assert.Equal(t, expected, stem)
+		})
+	}
+
+	t.Run("should match the official Snowball lists", func(t *testing.T) {
+		assertReferenceStems(t, "testdata/english", lexer.StemEnglish)
+	})
+}
+
+// assertReferenceStems checks the stemmer against voc.txt and output.txt lists of the directory.
+// The official Snowball lists are downloaded by "make testdata/snowball", the test is skipped without them.
+func assertReferenceStems(t *testing.T, dir string, stem func(string) string) {
+	t.Helper()
+
+	if _, err := os.Stat(path.Join(dir, "voc.txt")); errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("%s/voc.txt isn't downloaded, run make testdata/snowball", dir)
+	}
+	words := readLines(t, path.Join(dir, "voc.txt"))
+	stems := readLines(t, path.Join(dir, "output.txt"))
+	require.Len(t, stems, len(words))
+
+	failed := 0
+	for i, word := range words {
+		if !assert.Equal(t, stems[i], stem(word), word) {
+			failed++
+		}
+		if failed == 20 {
+			t.Fatalf("too many words are stemmed incorrectly")
+		}
+	}
+}
+
+func readLines(t *testing.T, name string) []string {
+	t.Helper()
+
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+
+	return lines
+}
+
+func TestEnglishStemFilter(t *testing.T) {
+	// given
+	analyzer, err := lexer.LookupAnalyzer(lexer.EnglishAnalyzer)
+	require.NoError(t, err)
+
+	// when
+	terms := analyzer.Analyze("Writing Examples by hand")
+
+	// expected
+	assert.Equal(t, []string{"write", "exampl", "by", "hand"}, terms)
+}
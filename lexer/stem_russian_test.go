@@ -0,0 +1,30 @@
+package lexer_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// russianStems are words checked by hand against the Snowball Russian steps.
+var russianStems = map[string]string{
+	"красивая": "красив", "красивейший": "красив", "поиск": "поиск", "поиска": "поиск", "поиском": "поиск",
+	"ёлка": "елк", "прочитавши": "прочита", "прочитав": "прочита", "написавшись": "написа",
+	"всё": "все", "её": "е", "ещё": "ещ", "включённый": "включен", "жёсткая": "жестк", "берётся": "берет",
+}
+
+func TestStemRussian(t *testing.T) {
+	for word, expected := range russianStems {
+		t.Run(word, func(t *testing.T) {
+			// when
+			stem := lexer.StemRussian(word)
+
+			// expected
+			assert.Equal(t, expected, stem)
+		})
+	}
+
+	t.Run("should match the official Snowball lists", func(t *testing.T) {
+		assertReferenceStems(t, "testdata/russian", lexer.StemRussian)
+	})
+}
@@ -0,0 +1,10 @@
+package lexer_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"testing"
+)
+
+func TestStemUkrainian(t *testing.T) {
+	assertReferenceStems(t, "testdata/ukrainian", lexer.StemUkrainian)
+}
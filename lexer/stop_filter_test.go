@@ -0,0 +1,103 @@
+package lexer_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStopFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		text     string
+		expected []string
+	}{
+		{name: "should remove English stopwords", language: "en", text: "The art of war is to win", expected: []string{"art", "war", "win"}},
+		{name: "should remove Ukrainian stopwords", language: "uk", text: "Це пошук і для вас", expected: []string{"пошук"}},
+		{name: "should remove Russian stopwords", language: "ru", text: "Это был поиск для вас", expected: []string{"это", "поиск"}},
+		{name: "should remove German stopwords", language: "de", text: "Die Suche und der Index", expected: []string{"suche", "index"}},
+		{name: "should remove French stopwords", language: "fr", text: "La recherche et les pages", expected: []string{"recherche", "pages"}},
+		{name: "should remove Spanish stopwords", language: "es", text: "La búsqueda de las páginas", expected: []string{"búsqueda", "páginas"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			words, err := lexer.Stopwords(tt.language)
+			require.NoError(t, err)
+			analyzer := lexer.Standard().With(lexer.StopFilter(words))
+
+			// when
+			terms := analyzer.Analyze(tt.text)
+
+			// expected
+			assert.Equal(t, tt.expected, terms)
+		})
+	}
+
+	t.Run("should compare stopwords case-insensitively before lowercasing and stemming", func(t *testing.T) {
+		// given
+		english, err := lexer.LookupAnalyzer(lexer.EnglishAnalyzer)
+		require.NoError(t, err)
+		analyzer := english.Prepend(lexer.StopFilter([]string{"DOES"}))
+
+		// when
+		terms := analyzer.Analyze("Does it work")
+
+		// expected
+		assert.Equal(t, []string{"it", "work"}, terms)
+		assert.Len(t, english.Filters, 2)
+	})
+
+	t.Run("should fail for languages without stopwords", func(t *testing.T) {
+		// when
+		_, err := lexer.Stopwords("xx")
+
+		// expected
+		assert.ErrorIs(t, err, lexer.ErrUnknownStopwords)
+		assert.Equal(t, []string{"de", "en", "es", "fr", "ru", "uk"}, lexer.StopwordLanguages())
+	})
+}
+
+func TestLoadStopwords(t *testing.T) {
+	t.Run("should load stopwords from a file", func(t *testing.T) {
+		// given
+		name := filepath.Join(t.TempDir(), "stopwords.txt")
+		content := strings.Join([]string{"# custom list", "foo", "", "bar  | Snowball comment", "baz # Lucene comment"}, "\n")
+		require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+
+		// when
+		words, err := lexer.LoadStopwords(name)
+
+		// expected
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foo", "bar", "baz"}, words)
+	})
+
+	t.Run("should fail for missing files", func(t *testing.T) {
+		// when
+		_, err := lexer.LoadStopwords(filepath.Join(t.TempDir(), "missing.txt"))
+
+		// expected
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestWithLanguageStopwords(t *testing.T) {
+	// given
+	analyzers := lexer.LanguageAnalyzers()
+
+	// when
+	stopAnalyzers := lexer.WithLanguageStopwords(analyzers)
+
+	// expected
+	assert.Len(t, stopAnalyzers, 6)
+	assert.Equal(t, []string{"write", "exampl"}, stopAnalyzers["en"].Analyze("Writing by examples"))
+	assert.Equal(t, []string{"suche"}, stopAnalyzers["de"].Analyze("Die Suche"))
+	assert.Equal(t, []string{"by"}, analyzers["en"].Analyze("by"))
+}
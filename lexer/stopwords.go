@@ -0,0 +1,108 @@
+package lexer
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+var ErrUnknownStopwords = errors.New("unknown stopwords language")
+
+//go:embed stopwords/*.txt
+var stopwords embed.FS
+
+// StopwordLanguages returns sorted ISO 639-1 codes of languages with built-in stopwords.
+func StopwordLanguages() []string {
+	files, _ := stopwords.ReadDir("stopwords")
+	languages := make([]string, 0, len(files))
+	for _, f := range files {
+		languages = append(languages, strings.TrimSuffix(f.Name(), ".txt"))
+	}
+	slices.Sort(languages)
+
+	return languages
+}
+
+// Stopwords returns built-in stopwords of the language by its ISO 639-1 code, e.g. "en".
+func Stopwords(language string) ([]string, error) {
+	f, err := stopwords.Open(path.Join("stopwords", language+".txt"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownStopwords, language)
+	}
+	defer func() { _ = f.Close() }()
+
+	return ReadStopwords(f)
+}
+
+// LoadStopwords reads stopwords from the file, see ReadStopwords for its format.
+func LoadStopwords(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open stopwords file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return ReadStopwords(f)
+}
+
+// ReadStopwords reads stopwords, one word per line. Blank lines and text after "#" or "|" are ignored,
+// so both Lucene and Snowball list formats are accepted.
+func ReadStopwords(r io.Reader) ([]string, error) {
+	words := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line, _, _ = strings.Cut(line, "|")
+		words = append(words, strings.Fields(line)...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read stopwords: %w", err)
+	}
+
+	return words, nil
+}
+
+// StopFilter removes stopwords from tokens, tokens are compared case-insensitively,
+// so the filter may go before LowercaseFilter and stemming filters.
+func StopFilter(words []string) TokenFilter {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[strings.ToLower(word)] = true
+	}
+
+	return TokenFilterFunc(func(tokens []Token) []Token {
+		return slices.DeleteFunc(tokens, func(token Token) bool {
+			return set[strings.ToLower(token.Text)]
+		})
+	})
+}
+
+// WithLanguageStopwords returns copies of analyzers by languages with StopFilter of built-in stopwords
+// placed before their filters. Languages with stopwords but without an analyzer get the Standard one.
+func WithLanguageStopwords(analyzers map[string]*Analyzer) map[string]*Analyzer {
+	result := make(map[string]*Analyzer, len(analyzers))
+	for lang, analyzer := range analyzers {
+		result[lang] = analyzer
+	}
+
+	for _, lang := range StopwordLanguages() {
+		words, err := Stopwords(lang)
+		if err != nil {
+			continue
+		}
+
+		analyzer, ok := result[lang]
+		if !ok {
+			analyzer = Standard()
+		}
+		result[lang] = analyzer.Prepend(StopFilter(words))
+	}
+
+	return result
+}
@@ -0,0 +1,132 @@
+package lexer_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const solrSynonyms = `
+# equivalent synonyms
+Kubernetes, k8s
+new york, nyc, big apple
+
+# one-way synonyms
+golang => go
+go lang, go-lang => go
+television, tv => tv
+`
+
+func TestSynonymFilter(t *testing.T) {
+	synonyms, err := lexer.ReadSynonyms(strings.NewReader(solrSynonyms))
+	require.NoError(t, err)
+	analyzer := lexer.Standard().With(lexer.SynonymFilter(synonyms))
+
+	tests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{name: "should expand equivalent synonyms", text: "K8s docs", expected: []string{"k8s", "kubernetes", "docs"}},
+		{name: "should replace words with one-way synonyms", text: "Golang tutorial", expected: []string{"go", "tutorial"}},
+		{name: "should not expand one-way synonyms backwards", text: "Go tutorial", expected: []string{"go", "tutorial"}},
+		{name: "should keep the word when it's its own synonym", text: "TV and television", expected: []string{"tv", "and", "tv"}},
+		{name: "should match multi-word phrases", text: "Pizza in New York", expected: []string{"pizza", "in", "new", "york", "nyc", "big", "apple"}},
+		{name: "should expand words to multi-word synonyms", text: "nyc", expected: []string{"nyc", "new", "york", "big", "apple"}},
+		{name: "should match the longest phrase", text: "go lang go", expected: []string{"go", "go"}},
+		{name: "should keep text without synonyms", text: "modules", expected: []string{"modules"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			terms := analyzer.Analyze(tt.text)
+
+			// expected
+			assert.Equal(t, tt.expected, terms)
+		})
+	}
+
+	t.Run("should give synonyms positions and offsets of the matched phrase", func(t *testing.T) {
+		// given
+		text := "in NYC now"
+
+		// when
+		tokens := analyzer.Tokens(text)
+
+		// expected
+		require.Len(t, tokens, 7)
+		assert.Equal(t, lexer.Token{Text: "new", Type: lexer.SynonymToken, Position: 1, Start: 3, End: 6, StartRune: 3, EndRune: 6}, tokens[2])
+		assert.Equal(t, lexer.Token{Text: "york", Type: lexer.SynonymToken, Position: 2, Start: 3, End: 6, StartRune: 3, EndRune: 6}, tokens[3])
+		assert.Equal(t, 2, tokens[6].Position)
+	})
+
+	t.Run("should stem synonyms by following filters", func(t *testing.T) {
+		// given
+		english := lexer.Standard().With(lexer.SynonymFilter(lexer.NewSynonyms().AddEquivalent("examples", "samples")), lexer.EnglishStemFilter())
+
+		// when
+		terms := english.Analyze("Examples")
+
+		// expected
+		assert.Equal(t, []string{"exampl", "sampl"}, terms)
+	})
+}
+
+func TestReadSynonyms(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "should fail for a rule without synonyms", text: "golang =>"},
+		{name: "should fail for a rule without phrases", text: "=> go"},
+		{name: "should fail for a rule with several arrows", text: "a => b => c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			_, err := lexer.ReadSynonyms(strings.NewReader("k8s, kubernetes\n" + tt.text))
+
+			// expected
+			assert.ErrorIs(t, err, lexer.ErrInvalidSynonyms)
+			assert.ErrorContains(t, err, "line 2")
+		})
+	}
+
+	t.Run("should load synonyms from a file", func(t *testing.T) {
+		// given
+		name := filepath.Join(t.TempDir(), "synonyms.txt")
+		require.NoError(t, os.WriteFile(name, []byte(solrSynonyms), 0o600))
+
+		// when
+		synonyms, err := lexer.LoadSynonyms(name)
+
+		// expected
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go"}, lexer.Standard().With(lexer.SynonymFilter(synonyms)).Analyze("golang"))
+	})
+
+	t.Run("should read WordNet synonyms", func(t *testing.T) {
+		// given
+		wordNet := strings.Join([]string{
+			"s(100001740,1,'entity',n,1,11).",
+			"s(102084071,1,'dog',n,1,42).",
+			"s(102084071,2,'domestic dog',n,1,0).",
+			"s(102084071,3,'Canis familiaris',n,1,0).",
+			"s(104543158,1,'o''clock',r,1,0).",
+		}, "\n")
+
+		// when
+		synonyms, err := lexer.ReadWordNetSynonyms(strings.NewReader(wordNet))
+
+		// expected
+		require.NoError(t, err)
+		terms := lexer.Standard().With(lexer.SynonymFilter(synonyms)).Analyze("Dog")
+		assert.Equal(t, []string{"dog", "domestic", "dog", "canis", "familiaris"}, terms)
+	})
+}
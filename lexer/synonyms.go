@@ -0,0 +1,222 @@
+package lexer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidSynonyms = errors.New("invalid synonym rule")
+
+var wordNetSynonymRe = regexp.MustCompile(`^s\((\d+),\d+,'((?:[^']|'')*)',`)
+
+// synonymRule are synonyms of a phrase, the phrase itself is kept only when keepOriginal is set.
+type synonymRule struct {
+	outputs      [][]string
+	keepOriginal bool
+}
+
+// Synonyms are rules which map words and phrases to their synonyms.
+// Words are compared case-insensitively, Synonyms must not be changed while they are used by a SynonymFilter.
+type Synonyms struct {
+	rules     map[string]*synonymRule
+	maxLength int
+}
+
+// NewSynonyms creates empty Synonyms.
+func NewSynonyms() *Synonyms {
+	return &Synonyms{rules: map[string]*synonymRule{}}
+}
+
+// AddEquivalent adds phrases which are synonyms of each other, e.g. "kubernetes" and "k8s".
+// Every phrase is expanded to all others and kept itself.
+func (s *Synonyms) AddEquivalent(phrases ...string) *Synonyms {
+	for _, phrase := range phrases {
+		s.add(phrase, phrases, true)
+	}
+
+	return s
+}
+
+// AddMapping adds a one-way rule which replaces the phrase with synonyms, e.g. "golang" with "go".
+// The phrase is kept only when it's one of synonyms.
+func (s *Synonyms) AddMapping(phrase string, synonyms ...string) *Synonyms {
+	s.add(phrase, synonyms, false)
+
+	return s
+}
+
+func (s *Synonyms) add(phrase string, synonyms []string, keepOriginal bool) {
+	words := strings.Fields(strings.ToLower(phrase))
+	if len(words) == 0 {
+		return
+	}
+
+	key := strings.Join(words, " ")
+	rule, ok := s.rules[key]
+	if !ok {
+		rule = &synonymRule{}
+		s.rules[key] = rule
+	}
+	rule.keepOriginal = rule.keepOriginal || keepOriginal
+	for _, synonym := range synonyms {
+		output := strings.Fields(strings.ToLower(synonym))
+		if len(output) == 0 || (keepOriginal && slices.Equal(output, words)) {
+			continue
+		}
+		if !slices.ContainsFunc(rule.outputs, func(o []string) bool { return slices.Equal(o, output) }) {
+			rule.outputs = append(rule.outputs, output)
+		}
+	}
+	s.maxLength = max(s.maxLength, len(words))
+}
+
+// match returns the rule of the longest phrase at the start of tokens and the number of its tokens.
+func (s *Synonyms) match(tokens []Token) (*synonymRule, int) {
+	for n := min(s.maxLength, len(tokens)); n > 0; n-- {
+		words := make([]string, n)
+		for i, token := range tokens[:n] {
+			words[i] = strings.ToLower(token.Text)
+		}
+		if rule, ok := s.rules[strings.Join(words, " ")]; ok {
+			return rule, n
+		}
+	}
+
+	return nil, 0
+}
+
+// LoadSynonyms reads synonyms in the Solr format from the file, see ReadSynonyms.
+func LoadSynonyms(name string) (*Synonyms, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open synonyms file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return ReadSynonyms(f)
+}
+
+// ReadSynonyms reads synonyms in the Solr format, one rule per line:
+//
+//	# equivalent synonyms, every phrase is expanded to all of them
+//	kubernetes, k8s
+//	new york, nyc, big apple
+//	# one-way synonyms, phrases on the left are replaced with phrases on the right
+//	golang => go
+//	go lang, go-lang => go
+func ReadSynonyms(r io.Reader) (*Synonyms, error) {
+	s := NewSynonyms()
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		from, to, oneWay := strings.Cut(text, "=>")
+		phrases, synonyms := splitSynonyms(from), splitSynonyms(to)
+		if len(phrases) == 0 || (oneWay && len(synonyms) == 0) || strings.Contains(to, "=>") {
+			return nil, fmt.Errorf("%w at line %d: %s", ErrInvalidSynonyms, line, text)
+		}
+
+		if !oneWay {
+			s.AddEquivalent(phrases...)
+			continue
+		}
+		for _, phrase := range phrases {
+			s.AddMapping(phrase, synonyms...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read synonyms: %w", err)
+	}
+
+	return s, nil
+}
+
+// ReadWordNetSynonyms reads synonyms in the WordNet prolog format, e.g. the wn_s.pl file,
+// words of the same synset are equivalent synonyms.
+func ReadWordNetSynonyms(r io.Reader) (*Synonyms, error) {
+	synsets := map[string][]string{}
+	ids := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		match := wordNetSynonymRe.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		if _, ok := synsets[match[1]]; !ok {
+			ids = append(ids, match[1])
+		}
+		synsets[match[1]] = append(synsets[match[1]], strings.ReplaceAll(match[2], "''", "'"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read synonyms: %w", err)
+	}
+
+	s := NewSynonyms()
+	for _, id := range ids {
+		if len(synsets[id]) > 1 {
+			s.AddEquivalent(synsets[id]...)
+		}
+	}
+
+	return s, nil
+}
+
+func splitSynonyms(text string) []string {
+	phrases := make([]string, 0)
+	for _, phrase := range strings.Split(text, ",") {
+		if phrase = strings.TrimSpace(phrase); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+
+	return phrases
+}
+
+// SynonymFilter adds synonyms of words and phrases as SynonymToken tokens, the longest matched phrase wins.
+// Synonyms get positions of the matched phrase and its offsets, so they can be highlighted in the text.
+//
+// The filter should go before stemming filters, then synonyms are stemmed with other tokens.
+// At query time synonyms can be changed without reindexing documents.
+func SynonymFilter(synonyms *Synonyms) TokenFilter {
+	return TokenFilterFunc(func(tokens []Token) []Token {
+		result := make([]Token, 0, len(tokens))
+		for i := 0; i < len(tokens); {
+			rule, n := synonyms.match(tokens[i:])
+			if rule == nil {
+				result = append(result, tokens[i])
+				i++
+				continue
+			}
+
+			first, last := tokens[i], tokens[i+n-1]
+			if rule.keepOriginal {
+				result = append(result, tokens[i:i+n]...)
+			}
+			for _, output := range rule.outputs {
+				for j, word := range output {
+					result = append(result, Token{
+						Text:      word,
+						Type:      SynonymToken,
+						Position:  first.Position + j,
+						Start:     first.Start,
+						End:       last.End,
+						StartRune: first.StartRune,
+						EndRune:   last.EndRune,
+					})
+				}
+			}
+			i += n
+		}
+
+		return result
+	})
+}
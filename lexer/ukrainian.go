@@ -0,0 +1,67 @@
+package lexer
+
+import "strings"
+
+// UkrainianAnalyzer is the name of the StandardAnalyzer followed by UkrainianStemFilter.
+const UkrainianAnalyzer = "ukrainian"
+
+// UkrainianStemFilter stems tokens by StemUkrainian, tokens must be lowercased before it.
+func UkrainianStemFilter() TokenFilter {
+	return stemFilter(StemUkrainian)
+}
+
+// Ending groups of the Ukrainian light stemmer, sorted by length in descending order.
+// Verb endings named "after" are removed only after а, я or и, which are removed too as the theme vowel.
+var (
+	ukrainianReflexive = []string{"ся", "сь"}
+	ukrainianAdjective = []string{"ього", "ьому", "ого", "ому", "ими", "іми", "ьою", "ьої", "ий", "ій", "им", "ім", "их", "іх", "ою", "ої", "ую", "юю"}
+	ukrainianVerbAfter = []string{"вши", "ти", "ла", "ло", "ли", "в"}
+	ukrainianVerb      = []string{
+		"аючи", "яючи", "аємо", "яємо", "аєте", "яєте", "аєть", "яєть", "ають", "яють", "аймо", "яймо", "айте", "яйте",
+		"аєш", "яєш", "ючи", "учи", "ачи", "ячи", "єть", "еть", "ємо", "емо", "имо", "імо", "їмо", "єте", "ете", "ите",
+		"іте", "їте", "ить", "іть", "їть", "ять", "ать", "уть", "ють", "ймо", "йте", "аю", "яю", "ає", "яє", "еш", "єш",
+		"иш", "їш",
+	}
+	ukrainianNoun = []string{"ями", "ами", "ові", "еві", "єві", "ах", "ях", "ам", "ям", "ів", "їв", "ей", "ом", "ем", "єм", "ею", "єю", "а", "я", "о", "е", "є", "у", "ю", "і", "ї", "и", "ь"}
+)
+
+// StemUkrainian returns the stem of a lowercased Ukrainian word by a light stemmer, e.g. "мов" for "мова" and "мовою".
+//
+// There is no Snowball algorithm for Ukrainian, so the stemmer follows the structure of the Russian one:
+// a reflexive ending is removed, then the longest adjective, verb or noun ending after the first vowel
+// and a doubled н. An ending starting with я, ю, є or ї after a vowel leaves й, so "шия" and "шиєю" become "ший",
+// "стояти" and "стою" become "стой". Alternations like "книга" and "книзі" aren't handled.
+func StemUkrainian(word string) string {
+	w := newSlavicWord(strings.NewReplacer("ʼ", "'", "’", "'").Replace(word), "аеєиіїоуюя")
+
+	w.remove(ukrainianReflexive, w.rv)
+	switch {
+	case w.remove(ukrainianAdjective, w.rv):
+	case w.removeAfter(ukrainianVerbAfter, w.rv, "аяи"):
+		removeIotated(w, []string{"а", "я", "и"})
+	case removeIotated(w, ukrainianVerb):
+	default:
+		removeIotated(w, ukrainianNoun)
+	}
+	if w.hasSuffix("нн", w.rv) {
+		w.undouble('н')
+	}
+
+	return string(w.runes)
+}
+
+// removeIotated removes the longest of suffixes in RV. When the suffix starts with я, ю, є or ї after a vowel,
+// the й they stand for is left at the end of the stem.
+func removeIotated(w *slavicWord, suffixes []string) bool {
+	suffix, ok := w.longest(suffixes, w.rv)
+	if !ok {
+		return false
+	}
+
+	w.runes = w.runes[:len(w.runes)-len([]rune(suffix))]
+	if strings.ContainsRune("яюєї", []rune(suffix)[0]) && len(w.runes) != 0 && w.isVowel(w.runes[len(w.runes)-1]) {
+		w.runes = append(w.runes, 'й')
+	}
+
+	return true
+}
@@ -1,28 +1,69 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/mishaprokop4ik/gorecs-search/crawler/web"
 	"github.com/mishaprokop4ik/gorecs-search/lexer"
 	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"log/slog"
+	"os"
 )
 
 func main() {
-	s := web.NewCrawler()
+	s := web.NewCrawler(
+		web.WithExtractionMode(web.ExtractionMainContent),
+		web.WithObserver(web.MultiObserver{
+			web.NewSlogObserver(slog.Default()),
+			web.NewProgressObserver(os.Stdout),
+		}),
+	)
 
-	content, err := s.Scrape("https://go.dev/learn/")
-	if err != nil {
+	const baseURL = "https://go.dev/learn/"
+	r := ranker.NewModel(map[string][]string{})
+	if err := r.SetAnalyzer(lexer.EnglishAnalyzer); err != nil {
 		panic(err)
 	}
+	r.LanguageAnalyzers = lexer.WithLanguageStopwords(lexer.LanguageAnalyzers())
+	for result := range s.Stream(context.Background(), baseURL) {
+		if result.Err != nil {
+			if result.Page.URL == baseURL {
+				panic(result.Err)
+			}
+			continue
+		}
+		// texts of links found after the page is indexed are sent when the crawl is finished.
+		if len(result.AnchorTexts) != 0 {
+			r.AddAnchorText(result.Page.URL, result.AnchorTexts...)
+			continue
+		}
 
-	r := ranker.NewModel(map[string][]string{})
-	for url, content := range content {
-		l := lexer.NewLexer(content...)
-		contentTokens := l.All()
-		r.AddDocuments(map[string][]string{
-			url: contentTokens,
+		page := result.Page
+		fields := make(map[ranker.Field][]string, len(page.Fields))
+		for name, text := range page.Fields {
+			fields[ranker.Field(name)] = text
+		}
+
+		published, _ := page.Metadata.Time(web.MetaPublished)
+		modified, _ := page.Metadata.Time(web.MetaModified)
+		r.Index(ranker.Document{
+			Path:      page.URL,
+			Fields:    fields,
+			Language:  page.Language,
+			Author:    page.Metadata[web.MetaAuthor],
+			Published: published,
+			Modified:  modified,
 		})
 	}
 
+	links := map[ranker.Path][]ranker.Path{}
+	for source, targets := range s.LinkGraph().Edges() {
+		for _, target := range targets {
+			links[ranker.Path(source)] = append(links[ranker.Path(source)], ranker.Path(target))
+		}
+	}
+	r.SetAuthority(ranker.PageRank(links, ranker.DefaultDamping, ranker.DefaultPageRankIterations))
+	r.AuthorityWeight = 0.1
+
 	fmt.Println(r.Rank("by", "examples"))
 }
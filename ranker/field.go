@@ -0,0 +1,43 @@
+package ranker
+
+import "time"
+
+// Field is a named part of a document, e.g. its title or body.
+type Field string
+
+const (
+	FieldTitle    Field = "title"
+	FieldHeadings Field = "headings"
+	FieldBody     Field = "body"
+	FieldLinks    Field = "links"
+	FieldAlt      Field = "alt"
+	FieldMeta     Field = "meta"
+	// FieldAnchors are texts of links to the document from other documents.
+	FieldAnchors Field = "anchors"
+)
+
+// DefaultFieldWeights returns weights which rank terms from the title and headings above the body ones,
+// while link texts and image attributes describe the page worse than its body.
+func DefaultFieldWeights() map[Field]float64 {
+	return map[Field]float64{
+		FieldTitle:    3,
+		FieldAnchors:  2,
+		FieldHeadings: 2,
+		FieldMeta:     1.5,
+		FieldBody:     1,
+		FieldAlt:      0.5,
+		FieldLinks:    0.5,
+	}
+}
+
+// Document is a document with text split by fields.
+type Document struct {
+	Path   string
+	Fields map[Field][]string
+	// Language is the ISO 639-1 code of the document language, empty when it's unknown.
+	Language string
+	Author   string
+	// Published and Modified are dates of the document, zero when they are unknown.
+	Published time.Time
+	Modified  time.Time
+}
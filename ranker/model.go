@@ -1,10 +1,10 @@
 package ranker
 
 import (
+	"fmt"
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
 	gorecslices "github.com/mishaprokop4ik/gorecs-search/pkg/slices"
 	"math"
-	"slices"
-	"sort"
 	"time"
 )
 
@@ -26,7 +26,8 @@ func NewModel(docs map[string][]string) *Model {
 	}
 
 	return &Model{
-		Docs: modelDocs,
+		Docs:         modelDocs,
+		FieldWeights: DefaultFieldWeights(),
 	}
 }
 
@@ -42,6 +43,7 @@ func (m *Model) AddDocuments(d map[string][]string) *Model {
 		for _, term := range terms {
 			doc.Terms[term] = gorecslices.Count(term, terms)
 		}
+		doc.Authority = m.authority[Path(path)]
 
 		m.Docs[Path(path)] = doc
 	}
@@ -49,6 +51,116 @@ func (m *Model) AddDocuments(d map[string][]string) *Model {
 	return m
 }
 
+// Index adds documents with per-field text to the ranking model.
+// Text of every field is split into terms by the Model Analyzer.
+func (m *Model) Index(docs ...Document) *Model {
+	for _, d := range docs {
+		doc := Doc{
+			Terms:     map[string]uint{},
+			Fields:    make(map[Field]map[string]uint, len(d.Fields)),
+			Language:  d.Language,
+			Author:    d.Author,
+			Published: d.Published,
+
+			lastModified: firstNonZero(d.Modified, d.Published, time.Now()),
+		}
+
+		for field, text := range d.Fields {
+			doc.addFieldTerms(field, m.analyze(m.analyzer(d.Language, false), text...))
+		}
+		if anchors := m.anchors[Path(d.Path)]; len(anchors) != 0 {
+			doc.addFieldTerms(FieldAnchors, m.analyze(m.analyzer(d.Language, false), anchors...))
+		}
+		doc.Authority = m.authority[Path(d.Path)]
+
+		m.Docs[Path(d.Path)] = doc
+	}
+
+	return m
+}
+
+// AddAnchorText adds texts of links pointing to the document by path to its FieldAnchors.
+// Text may be added before the document itself, it's attached when the document is indexed by Index
+// and analyzed in the document language.
+// Documents added by AddDocuments don't have fields, so anchor text isn't used for them.
+func (m *Model) AddAnchorText(path string, text ...string) *Model {
+	if m.anchors == nil {
+		m.anchors = map[Path][]string{}
+	}
+	m.anchors[Path(path)] = append(m.anchors[Path(path)], text...)
+
+	if doc, ok := m.Docs[Path(path)]; ok && len(doc.Fields) != 0 {
+		doc.addFieldTerms(FieldAnchors, m.analyze(m.analyzer(doc.Language, false), text...))
+	}
+
+	return m
+}
+
+// SetAnalyzer sets the Analyzer registered in lexer by name, lexer.ErrUnknownAnalyzer is returned for unknown names.
+func (m *Model) SetAnalyzer(name string) error {
+	analyzer, err := lexer.LookupAnalyzer(name)
+	if err != nil {
+		return fmt.Errorf("cannot set analyzer: %w", err)
+	}
+	m.Analyzer = analyzer
+
+	return nil
+}
+
+// SetQueryAnalyzer sets the QueryAnalyzer registered in lexer by name, lexer.ErrUnknownAnalyzer is returned for unknown names.
+func (m *Model) SetQueryAnalyzer(name string) error {
+	analyzer, err := lexer.LookupAnalyzer(name)
+	if err != nil {
+		return fmt.Errorf("cannot set query analyzer: %w", err)
+	}
+	m.QueryAnalyzer = analyzer
+
+	return nil
+}
+
+// analyzer returns the analyzer of the language, an analyzer of the language is preferred over a generic one
+// and query analyzers are preferred over index ones for queries. Query analyzers expand QuerySynonyms.
+func (m *Model) analyzer(language string, query bool) *lexer.Analyzer {
+	result := lexer.Standard()
+	analyzers := []*lexer.Analyzer{m.LanguageAnalyzers[language], m.Analyzer}
+	if query {
+		analyzers = []*lexer.Analyzer{m.QueryLanguageAnalyzers[language], m.LanguageAnalyzers[language], m.QueryAnalyzer, m.Analyzer}
+	}
+	for _, analyzer := range analyzers {
+		if analyzer != nil {
+			result = analyzer
+			break
+		}
+	}
+
+	if query && m.QuerySynonyms != nil {
+		return result.Prepend(lexer.SynonymFilter(m.QuerySynonyms))
+	}
+
+	return result
+}
+
+// analyze splits every text block into terms separately, so words of adjacent blocks aren't glued.
+func (m *Model) analyze(analyzer *lexer.Analyzer, text ...string) []string {
+	terms := make([]string, 0)
+	for _, block := range text {
+		terms = append(terms, analyzer.Analyze(block)...)
+	}
+
+	return terms
+}
+
+func (d *Doc) addFieldTerms(field Field, terms []string) {
+	if d.Fields[field] == nil {
+		d.Fields[field] = map[string]uint{}
+	}
+
+	for _, term := range terms {
+		d.Fields[field][term]++
+		d.Terms[term]++
+	}
+}
+
 type DocumentStorer interface {
 	Save(path Path, doc Doc) error
 	Get(path Path) (Doc, error)
@@ -65,46 +177,77 @@ type RankStorer interface {
 // Provides results for Model's Docs.
 type Model struct {
 	Docs Docs
+	// FieldWeights are multipliers of a term rank found in a field.
+	// Fields without weight are weighted as 1.
+	FieldWeights map[Field]float64
+	// AuthorityWeight is a multiplier of the normalized document Authority added to its tf-idf rank.
+	// Zero disables authority ranking.
+	AuthorityWeight float64
+	// Analyzer splits text of indexed documents and queries into terms, lexer.Standard is used when it's nil.
+	// Documents added by NewModel and AddDocuments are already split, so only queries are analyzed for them.
+	Analyzer *lexer.Analyzer
+	// LanguageAnalyzers are analyzers of documents by their Language, e.g. stemmers from lexer.LanguageAnalyzers.
+	// Query terms are analyzed by the analyzer of every matched document, Analyzer is used for other languages.
+	LanguageAnalyzers map[string]*lexer.Analyzer
+	// QueryAnalyzer and QueryLanguageAnalyzers replace Analyzer and LanguageAnalyzers for queries when they are set,
+	// e.g. to remove stopwords only from queries. They must produce the same terms as index analyzers otherwise.
+	QueryAnalyzer          *lexer.Analyzer
+	QueryLanguageAnalyzers map[string]*lexer.Analyzer
+	// QuerySynonyms expand query terms by lexer.SynonymFilter placed before filters of query analyzers,
+	// so synonyms may be changed without reindexing documents.
+	QuerySynonyms *lexer.Synonyms
 	// TODO: maybe it should be moved to another struct as Model shouldn't think about Storing stuff, it should think only about Ranking...
 	DocumentStore DocumentStorer
 	RankStore     RankStorer
+
+	// anchors are anchor texts by the link target, including targets which aren't indexed yet.
+	anchors map[Path][]string
+	// authority are static scores by documents, including documents which aren't indexed yet.
+	authority map[Path]float64
 }
 
 type Docs map[Path]Doc
 
 type Doc struct {
+	// Terms are counts of all document terms.
 	Terms map[string]uint
+	// Fields are counts of terms by fields, empty for documents added without fields.
+	Fields map[Field]map[string]uint
+	// Authority is a static score of the document independent of queries, e.g. its PageRank.
+	Authority float64
+	// Language is the ISO 639-1 code of the document language, empty when it's unknown.
+	Language string
+	Author   string
+	// Published is the publish date of the document, zero when it's unknown.
+	Published time.Time
 
 	lastModified time.Time
 }
 
-type DocFreq map[Path]float64
-
-type Path string
-
-// Rank returns sorted by if-idf rank function paths.
-func (m *Model) Rank(keyWords ...string) []Path {
-	docFreq := DocFreq{}
+// LastModified returns the modification date of the document,
+// the time the document was added to the Model is used when it's unknown.
+func (d Doc) LastModified() time.Time {
+	return d.lastModified
+}
 
-	for path := range m.Docs {
-		rank := float64(0)
-		for _, term := range keyWords {
-			rank += m.computeTFIDF(term, path)
+func firstNonZero(times ...time.Time) time.Time {
+	for _, t := range times {
+		if !t.IsZero() {
+			return t
 		}
-		docFreq[path] = rank
 	}
 
-	keys := make([]Path, 0, len(docFreq))
-	for key := range docFreq {
-		keys = append(keys, key)
-	}
-	sort.Slice(keys, func(i, j int) bool { return docFreq[keys[i]] > docFreq[keys[j]] })
+	return time.Time{}
+}
 
-	keys = slices.DeleteFunc(keys, func(path Path) bool {
-		return docFreq[path] <= 0
-	})
+type DocFreq map[Path]float64
+
+type Path string
 
-	return keys
+// Rank returns sorted by if-idf rank function paths.
+// It's the Query of keyWords in documents of all languages.
+func (m *Model) Rank(keyWords ...string) []Path {
+	return m.Query(Query{Terms: keyWords})
 }
 
 // computeTermFrequency calculates tf for a term by a documentPath.
@@ -144,7 +287,34 @@ func (m *Model) computeInverseDocumentFrequency(term string) float64 {
 	return math.Log10(totalDocNumber / math.Max(termAppearsCount, 1))
 }
 
+// computeFieldTermFrequency calculates tf for a term by a documentPath as a sum of tf
+// in every document field multiplied by the field weight.
+func (m *Model) computeFieldTermFrequency(term string, documentPath Path) float64 {
+	document := m.Docs[documentPath]
+	tf := float64(0)
+	for field, terms := range document.Fields {
+		if termFreq := terms[term]; termFreq > 0 {
+			tf += m.fieldWeight(field) * float64(termFreq) / float64(len(terms))
+		}
+	}
+
+	return tf
+}
+
+func (m *Model) fieldWeight(field Field) float64 {
+	if weight, ok := m.FieldWeights[field]; ok {
+		return weight
+	}
+
+	return 1
+}
+
 // computeTFIDF calculates multiplication of computeTermFrequency and computeInverseDocumentFrequency.
+// For documents with fields computeFieldTermFrequency is used instead of computeTermFrequency.
 func (m *Model) computeTFIDF(term string, documentPath Path) float64 {
+	if len(m.Docs[documentPath].Fields) != 0 {
+		return m.computeFieldTermFrequency(term, documentPath) * m.computeInverseDocumentFrequency(term)
+	}
+
 	return m.computeTermFrequency(term, documentPath) * m.computeInverseDocumentFrequency(term)
 }
@@ -0,0 +1,181 @@
+package ranker_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestModel_Analyzer(t *testing.T) {
+	docs := []ranker.Document{
+		{Path: "tutorial", Fields: map[ranker.Field][]string{ranker.FieldBody: {"Go Generics tutorial"}}},
+		{Path: "modules", Fields: map[ranker.Field][]string{ranker.FieldBody: {"Go modules reference"}}},
+	}
+
+	t.Run("should analyze queries like documents", func(t *testing.T) {
+		// given
+		m := ranker.NewModel(map[string][]string{})
+		m.Index(docs...)
+
+		// when
+		paths := m.Rank("GENERICS, tutorial!")
+
+		// expected
+		assert.Equal(t, []ranker.Path{"tutorial"}, paths)
+		assert.Greater(t, m.Relevance("tutorial", "Generics"), float64(0))
+	})
+
+	t.Run("should index documents by the model analyzer", func(t *testing.T) {
+		// given
+		m := ranker.NewModel(map[string][]string{})
+		m.Analyzer = lexer.Standard().With(lexer.TokenFilterFunc(func(tokens []lexer.Token) []lexer.Token {
+			for i := range tokens {
+				tokens[i].Text = strings.TrimSuffix(tokens[i].Text, "s")
+			}
+			return tokens
+		}))
+		m.Index(docs...)
+
+		// when
+		paths := m.Rank("module")
+
+		// expected
+		assert.Equal(t, []ranker.Path{"modules"}, paths)
+		assert.Contains(t, m.Docs["tutorial"].Terms, "generic")
+	})
+}
+
+func TestModel_AnalyzerEnglish(t *testing.T) {
+	// given
+	english, err := lexer.LookupAnalyzer(lexer.EnglishAnalyzer)
+	require.NoError(t, err)
+	stemmed, plain := ranker.NewModel(map[string][]string{}), ranker.NewModel(map[string][]string{})
+	stemmed.Analyzer = english
+	for _, m := range []*ranker.Model{stemmed, plain} {
+		m.Index(
+			ranker.Document{Path: "example", Fields: map[ranker.Field][]string{ranker.FieldBody: {"Write an example"}}},
+			ranker.Document{Path: "other", Fields: map[ranker.Field][]string{ranker.FieldBody: {"Read the docs"}}},
+		)
+	}
+
+	// when
+	stemmedPaths, plainPaths := stemmed.Rank("writing", "examples"), plain.Rank("writing", "examples")
+
+	// expected
+	assert.Equal(t, []ranker.Path{"example"}, stemmedPaths)
+	assert.Empty(t, plainPaths)
+}
+
+func TestModel_LanguageAnalyzers(t *testing.T) {
+	// given
+	m := ranker.NewModel(map[string][]string{})
+	m.LanguageAnalyzers = lexer.LanguageAnalyzers()
+	m.Index(
+		ranker.Document{
+			Path:     "uk",
+			Fields:   map[ranker.Field][]string{ranker.FieldBody: {"Пошук документів українською мовою"}},
+			Language: "uk",
+		},
+		ranker.Document{
+			Path:     "ru",
+			Fields:   map[ranker.Field][]string{ranker.FieldBody: {"Поиск документов на русском языке"}},
+			Language: "ru",
+		},
+		ranker.Document{
+			Path:     "en",
+			Fields:   map[ranker.Field][]string{ranker.FieldBody: {"Searching documents in English"}},
+			Language: "en",
+		},
+	)
+	m.AddAnchorText("uk", "Українські мови")
+
+	tests := []struct {
+		name     string
+		query    ranker.Query
+		expected []ranker.Path
+	}{
+		{
+			name:     "should stem Ukrainian documents and queries",
+			query:    ranker.Query{Terms: []string{"пошуку", "мова"}},
+			expected: []ranker.Path{"uk"},
+		},
+		{
+			name:     "should stem Russian documents and queries",
+			query:    ranker.Query{Terms: []string{"поиска", "документы"}},
+			expected: []ranker.Path{"ru"},
+		},
+		{
+			name:     "should stem English documents and queries",
+			query:    ranker.Query{Terms: []string{"search", "document"}},
+			expected: []ranker.Path{"en"},
+		},
+		{
+			name:     "should stem anchor texts in the document language",
+			query:    ranker.Query{Terms: []string{"українська"}},
+			expected: []ranker.Path{"uk"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			paths := m.Query(tt.query)
+
+			// expected
+			assert.Equal(t, tt.expected, paths)
+		})
+	}
+}
+
+func TestModel_AnalyzerCJK(t *testing.T) {
+	// given
+	m := ranker.NewModel(map[string][]string{})
+	m.Index(
+		ranker.Document{Path: "tokyo", Fields: map[ranker.Field][]string{ranker.FieldBody: {"東京都の検索エンジン"}}},
+		ranker.Document{Path: "kyoto", Fields: map[ranker.Field][]string{ranker.FieldBody: {"京都府の観光案内"}}},
+	)
+
+	// when
+	paths := m.Rank("検索")
+
+	// expected
+	assert.Equal(t, []ranker.Path{"tokyo"}, paths)
+	assert.Equal(t, []ranker.Path{"kyoto"}, m.Rank("観光"))
+}
+
+func TestModel_SetAnalyzer(t *testing.T) {
+	t.Run("should set analyzers by name", func(t *testing.T) {
+		// given
+		m := ranker.NewModel(map[string][]string{})
+		english, err := lexer.LookupAnalyzer(lexer.EnglishAnalyzer)
+		require.NoError(t, err)
+
+		// when
+		analyzerErr := m.SetAnalyzer(lexer.EnglishAnalyzer)
+		queryErr := m.SetQueryAnalyzer(lexer.KeywordAnalyzer)
+
+		// expected
+		require.NoError(t, analyzerErr)
+		require.NoError(t, queryErr)
+		assert.Same(t, english, m.Analyzer)
+		assert.NotNil(t, m.QueryAnalyzer)
+	})
+
+	t.Run("should return an error for unknown names", func(t *testing.T) {
+		// given
+		m := ranker.NewModel(map[string][]string{})
+
+		// when
+		analyzerErr := m.SetAnalyzer("unknown")
+		queryErr := m.SetQueryAnalyzer("unknown")
+
+		// expected
+		assert.ErrorIs(t, analyzerErr, lexer.ErrUnknownAnalyzer)
+		assert.ErrorIs(t, queryErr, lexer.ErrUnknownAnalyzer)
+		assert.Nil(t, m.Analyzer)
+		assert.Nil(t, m.QueryAnalyzer)
+	})
+}
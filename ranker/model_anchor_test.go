@@ -0,0 +1,39 @@
+package ranker_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestModel_AddAnchorText(t *testing.T) {
+	m := ranker.NewModel(map[string][]string{})
+
+	// the link is found before the target is indexed
+	m.AddAnchorText("https://go.dev/tour/", "Go tour")
+	m.Index(
+		ranker.Document{
+			Path:   "https://go.dev/tour/",
+			Fields: map[ranker.Field][]string{ranker.FieldBody: {"Welcome to a tour of the Go programming language."}},
+		},
+		ranker.Document{
+			Path:   "https://go.dev/learn/",
+			Fields: map[ranker.Field][]string{ranker.FieldBody: {"Learn the Go programming language."}},
+		},
+		ranker.Document{
+			Path:   "https://go.dev/play/",
+			Fields: map[ranker.Field][]string{ranker.FieldBody: {"Go playground."}},
+		},
+	)
+	// the link is found after the target is indexed
+	m.AddAnchorText("https://go.dev/play/", "Run Go in a browser")
+
+	t.Run("should add anchor text field to the document", func(t *testing.T) {
+		assert.Equal(t, map[string]uint{"go": 1, "tour": 1}, m.Docs["https://go.dev/tour/"].Fields[ranker.FieldAnchors])
+		assert.Equal(t, uint(2), m.Docs["https://go.dev/tour/"].Terms["tour"])
+	})
+
+	t.Run("should rank documents by anchor text added after indexing", func(t *testing.T) {
+		assert.Equal(t, []ranker.Path{"https://go.dev/play/"}, m.Rank("browser"))
+	})
+}
@@ -0,0 +1,70 @@
+package ranker_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestModel_Index(t *testing.T) {
+	m := ranker.NewModel(map[string][]string{})
+
+	m.Index(
+		ranker.Document{
+			Path: "footer",
+			Fields: map[ranker.Field][]string{
+				ranker.FieldTitle: {"Release notes"},
+				ranker.FieldBody:  {"Go 1.18 release notes, generics are mentioned in the footer."},
+			},
+		},
+		ranker.Document{
+			Path: "tutorial",
+			Fields: map[ranker.Field][]string{
+				ranker.FieldTitle: {"Generics tutorial"},
+				ranker.FieldBody:  {"Go 1.18 release introduced type parameters."},
+			},
+		},
+		ranker.Document{
+			Path: "other",
+			Fields: map[ranker.Field][]string{
+				ranker.FieldBody: {"Effective Go"},
+			},
+		},
+	)
+
+	t.Run("should rank title match above body match", func(t *testing.T) {
+		assert.Equal(t, []ranker.Path{"tutorial", "footer"}, m.Rank("generics"))
+	})
+
+	t.Run("should use custom field weights", func(t *testing.T) {
+		m.FieldWeights = map[ranker.Field]float64{
+			ranker.FieldTitle: 0.1,
+		}
+
+		assert.Equal(t, []ranker.Path{"footer", "tutorial"}, m.Rank("generics"))
+	})
+
+	t.Run("should count terms of all fields", func(t *testing.T) {
+		assert.Equal(t, uint(2), m.Docs["footer"].Terms["notes"])
+		assert.Equal(t, uint(1), m.Docs["footer"].Fields[ranker.FieldTitle]["notes"])
+	})
+}
+
+func TestModel_IndexDates(t *testing.T) {
+	published := time.Date(2022, 3, 22, 0, 0, 0, 0, time.UTC)
+	modified := published.Add(24 * time.Hour)
+
+	m := ranker.NewModel(map[string][]string{})
+	m.Index(
+		ranker.Document{Path: "modified", Author: "Ian Lance Taylor", Published: published, Modified: modified},
+		ranker.Document{Path: "published", Published: published},
+		ranker.Document{Path: "unknown"},
+	)
+
+	assert.Equal(t, "Ian Lance Taylor", m.Docs["modified"].Author)
+	assert.Equal(t, published, m.Docs["modified"].Published)
+	assert.Equal(t, modified, m.Docs["modified"].LastModified())
+	assert.Equal(t, published, m.Docs["published"].LastModified())
+	assert.WithinDuration(t, time.Now(), m.Docs["unknown"].LastModified(), time.Minute)
+}
@@ -0,0 +1,61 @@
+package ranker_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestModel_Query(t *testing.T) {
+	m := ranker.NewModel(map[string][]string{})
+	m.Index(
+		ranker.Document{
+			Path:     "en",
+			Fields:   map[ranker.Field][]string{ranker.FieldBody: {"Go generics tutorial"}},
+			Language: "en",
+		},
+		ranker.Document{
+			Path:     "uk",
+			Fields:   map[ranker.Field][]string{ranker.FieldBody: {"Go generics посібник"}},
+			Language: "uk",
+		},
+		ranker.Document{
+			Path:   "unknown",
+			Fields: map[ranker.Field][]string{ranker.FieldBody: {"generics"}},
+		},
+		ranker.Document{
+			Path:   "other",
+			Fields: map[ranker.Field][]string{ranker.FieldBody: {"modules"}},
+		},
+	)
+
+	testCases := []struct {
+		name     string
+		query    ranker.Query
+		expected []ranker.Path
+	}{
+		{
+			name:     "should match documents of all languages",
+			query:    ranker.Query{Terms: []string{"generics"}},
+			expected: []ranker.Path{"en", "uk", "unknown"},
+		},
+		{
+			name:     "should match only documents of the language",
+			query:    ranker.Query{Terms: []string{"generics"}, Language: "uk"},
+			expected: []ranker.Path{"uk"},
+		},
+		{
+			name:     "should not match documents of unknown language by the language",
+			query:    ranker.Query{Terms: []string{"generics"}, Language: "de"},
+			expected: []ranker.Path{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tc.expected, m.Query(tc.query))
+		})
+	}
+
+	assert.Equal(t, "uk", m.Docs["uk"].Language)
+}
@@ -0,0 +1,52 @@
+package ranker_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestModel_Relevance(t *testing.T) {
+	m := ranker.NewModel(map[string][]string{
+		"plain": {"generics", "in", "go", "generics"},
+	})
+	m.Index(
+		ranker.Document{
+			Path: "title",
+			Fields: map[ranker.Field][]string{
+				ranker.FieldTitle: {"Generics"},
+				ranker.FieldBody:  {"Type parameters in Go"},
+			},
+		},
+		ranker.Document{
+			Path: "body",
+			Fields: map[ranker.Field][]string{
+				ranker.FieldBody: {"Generics in Go"},
+			},
+		},
+	)
+
+	t.Run("should weight terms by fields", func(t *testing.T) {
+		assert.Greater(t, m.Relevance("title", "generics"), m.Relevance("body", "generics"))
+	})
+
+	t.Run("should average relevance by keywords", func(t *testing.T) {
+		assert.InDelta(t, m.Relevance("body", "generics")/2, m.Relevance("body", "generics", "modules"), 1e-9)
+	})
+
+	t.Run("should not depend on other documents", func(t *testing.T) {
+		before := m.Relevance("body", "generics")
+		m.Index(ranker.Document{Path: "other", Fields: map[ranker.Field][]string{ranker.FieldBody: {"generics"}}})
+
+		assert.InDelta(t, before, m.Relevance("body", "generics"), 1e-9)
+	})
+
+	t.Run("should use terms of documents without fields", func(t *testing.T) {
+		assert.InDelta(t, 0.632, m.Relevance("plain", "generics"), 1e-3)
+	})
+
+	t.Run("should return zero for unknown documents and empty queries", func(t *testing.T) {
+		assert.Zero(t, m.Relevance("unknown", "generics"))
+		assert.Zero(t, m.Relevance("body"))
+	})
+}
@@ -0,0 +1,82 @@
+package ranker_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestModel_Stopwords(t *testing.T) {
+	plain := lexer.LanguageAnalyzers()
+	stop := lexer.WithLanguageStopwords(plain)
+	docs := []ranker.Document{
+		{Path: "war", Fields: map[ranker.Field][]string{ranker.FieldBody: {"The art of war"}}, Language: "en"},
+		{Path: "peace", Fields: map[ranker.Field][]string{ranker.FieldBody: {"The way to peace"}}, Language: "en"},
+	}
+
+	tests := []struct {
+		name           string
+		index          map[string]*lexer.Analyzer
+		query          map[string]*lexer.Analyzer
+		indexed        bool
+		expectedTheWar []ranker.Path
+	}{
+		{
+			name:           "should keep stopwords without stopword filters",
+			index:          plain,
+			indexed:        true,
+			expectedTheWar: []ranker.Path{"war"},
+		},
+		{
+			name:           "should remove stopwords at index time",
+			index:          stop,
+			expectedTheWar: []ranker.Path{"war"},
+		},
+		{
+			name:           "should remove stopwords at query time",
+			index:          plain,
+			query:          stop,
+			indexed:        true,
+			expectedTheWar: []ranker.Path{"war"},
+		},
+		{
+			name:           "should remove stopwords at index and query time",
+			index:          stop,
+			query:          stop,
+			expectedTheWar: []ranker.Path{"war"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			m := ranker.NewModel(map[string][]string{})
+			m.LanguageAnalyzers, m.QueryLanguageAnalyzers = tt.index, tt.query
+			m.Index(docs...)
+
+			// when
+			theWar := m.Rank("the", "war")
+			the := m.Rank("the")
+
+			// expected
+			assert.Equal(t, tt.expectedTheWar, theWar)
+			assert.Empty(t, the)
+			_, ok := m.Docs["war"].Terms["the"]
+			assert.Equal(t, tt.indexed, ok)
+		})
+	}
+
+	t.Run("should not rank documents by stopwords of the query", func(t *testing.T) {
+		// given
+		m := ranker.NewModel(map[string][]string{})
+		m.LanguageAnalyzers, m.QueryLanguageAnalyzers = plain, stop
+		m.Index(docs...)
+
+		// when
+		paths := m.Rank("to", "war")
+
+		// expected
+		assert.Equal(t, []ranker.Path{"war"}, paths)
+	})
+}
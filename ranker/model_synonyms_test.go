@@ -0,0 +1,44 @@
+package ranker_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/lexer"
+	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestModel_QuerySynonyms(t *testing.T) {
+	// given
+	synonyms, err := lexer.ReadSynonyms(strings.NewReader("kubernetes, k8s\ngolang => go\nnew york, nyc"))
+	require.NoError(t, err)
+	m := ranker.NewModel(map[string][]string{})
+	m.LanguageAnalyzers = lexer.LanguageAnalyzers()
+	m.Index(
+		ranker.Document{Path: "k8s", Fields: map[ranker.Field][]string{ranker.FieldBody: {"Deploying to Kubernetes clusters"}}, Language: "en"},
+		ranker.Document{Path: "go", Fields: map[ranker.Field][]string{ranker.FieldBody: {"The Go programming language"}}, Language: "en"},
+		ranker.Document{Path: "nyc", Fields: map[ranker.Field][]string{ranker.FieldBody: {"Pizza places in NYC"}}},
+	)
+
+	t.Run("should not expand queries without synonyms", func(t *testing.T) {
+		// expected
+		assert.Empty(t, m.Rank("k8s"))
+		assert.Empty(t, m.Rank("golang"))
+	})
+
+	t.Run("should expand queries with synonyms without reindexing", func(t *testing.T) {
+		// given
+		m.QuerySynonyms = synonyms
+		defer func() { m.QuerySynonyms = nil }()
+
+		// when
+		k8s, golang, newYork := m.Rank("k8s"), m.Rank("golang"), m.Rank("New York")
+
+		// expected
+		assert.Equal(t, []ranker.Path{"k8s"}, k8s)
+		assert.Equal(t, []ranker.Path{"go"}, golang)
+		assert.Equal(t, []ranker.Path{"nyc"}, newYork)
+		assert.NotContains(t, m.Docs["k8s"].Terms, "k8s")
+	})
+}
@@ -0,0 +1,87 @@
+package ranker
+
+const (
+	// DefaultDamping is the probability to follow a link instead of jumping to a random document.
+	DefaultDamping = 0.85
+	// DefaultPageRankIterations is enough for scores of most web graphs to converge.
+	DefaultPageRankIterations = 30
+)
+
+// PageRank computes PageRank scores of documents by links between them.
+// See more - https://en.wikipedia.org/wiki/PageRank.
+//
+// links are targets of links by the source document. Documents without outgoing links
+// share their score between all documents. Scores of all documents sum up to 1.
+func PageRank(links map[Path][]Path, damping float64, iterations int) map[Path]float64 {
+	nodes := map[Path]struct{}{}
+	for source, targets := range links {
+		nodes[source] = struct{}{}
+		for _, target := range targets {
+			nodes[target] = struct{}{}
+		}
+	}
+	if len(nodes) == 0 {
+		return map[Path]float64{}
+	}
+
+	n := float64(len(nodes))
+	scores := make(map[Path]float64, len(nodes))
+	for node := range nodes {
+		scores[node] = 1 / n
+	}
+
+	for range iterations {
+		danglingScore := float64(0)
+		for node := range nodes {
+			if len(links[node]) == 0 {
+				danglingScore += scores[node]
+			}
+		}
+
+		next := make(map[Path]float64, len(nodes))
+		for node := range nodes {
+			next[node] = (1-damping)/n + damping*danglingScore/n
+		}
+		for source, targets := range links {
+			if len(targets) == 0 {
+				continue
+			}
+			share := damping * scores[source] / float64(len(targets))
+			for _, target := range targets {
+				next[target] += share
+			}
+		}
+
+		scores = next
+	}
+
+	return scores
+}
+
+// SetAuthority sets static authority scores of documents, e.g. computed by PageRank.
+// Scores of documents which aren't indexed yet are set when they are added.
+func (m *Model) SetAuthority(scores map[Path]float64) *Model {
+	if m.authority == nil {
+		m.authority = map[Path]float64{}
+	}
+
+	for path, score := range scores {
+		m.authority[path] = score
+		if doc, ok := m.Docs[path]; ok {
+			doc.Authority = score
+			m.Docs[path] = doc
+		}
+	}
+
+	return m
+}
+
+// maxAuthority returns the maximal document authority in the model.
+func (m *Model) maxAuthority() float64 {
+	result := float64(0)
+	for _, doc := range m.Docs {
+		result = max(result, doc.Authority)
+	}
+
+	return result
+}
@@ -0,0 +1,75 @@
+package ranker_test
+
+import (
+	"github.com/mishaprokop4ik/gorecs-search/ranker"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPageRank(t *testing.T) {
+	t.Run("should share score equally in a cycle", func(t *testing.T) {
+		scores := ranker.PageRank(map[ranker.Path][]ranker.Path{
+			"a": {"b"},
+			"b": {"c"},
+			"c": {"a"},
+		}, ranker.DefaultDamping, ranker.DefaultPageRankIterations)
+
+		for _, path := range []ranker.Path{"a", "b", "c"} {
+			assert.InDelta(t, 1.0/3, scores[path], 1e-9)
+		}
+	})
+
+	t.Run("should rank hub above linking pages", func(t *testing.T) {
+		scores := ranker.PageRank(map[ranker.Path][]ranker.Path{
+			"a":   {"hub"},
+			"b":   {"hub"},
+			"hub": {"a"},
+			// orphan doesn't have incoming links and outgoing links
+			"orphan": {},
+		}, ranker.DefaultDamping, ranker.DefaultPageRankIterations)
+
+		sum := float64(0)
+		for _, score := range scores {
+			sum += score
+		}
+		assert.InDelta(t, 1, sum, 1e-9)
+		assert.Greater(t, scores["hub"], scores["a"])
+		assert.Greater(t, scores["a"], scores["b"])
+		assert.InDelta(t, scores["b"], scores["orphan"], 1e-9)
+	})
+
+	t.Run("should return empty scores for empty graph", func(t *testing.T) {
+		assert.Empty(t, ranker.PageRank(nil, ranker.DefaultDamping, ranker.DefaultPageRankIterations))
+	})
+}
+
+func TestModel_RankWithAuthority(t *testing.T) {
+	m := ranker.NewModel(map[string][]string{
+		"fragment": {"generics", "type", "parameters"},
+		"hub":      {"generics", "type", "parameters"},
+		"other":    {"modules"},
+	})
+	m.SetAuthority(ranker.PageRank(map[ranker.Path][]ranker.Path{
+		"fragment": {"hub"},
+		"other":    {"hub"},
+		"hub":      {"other"},
+	}, ranker.DefaultDamping, ranker.DefaultPageRankIterations))
+
+	t.Run("should not change tf-idf rank without weight", func(t *testing.T) {
+		assert.ElementsMatch(t, []ranker.Path{"fragment", "hub"}, m.Rank("generics"))
+	})
+
+	t.Run("should rank well-linked document above orphaned one", func(t *testing.T) {
+		m.AuthorityWeight = 0.5
+
+		assert.Equal(t, []ranker.Path{"hub", "fragment"}, m.Rank("generics"))
+	})
+
+	t.Run("should set authority of documents added later", func(t *testing.T) {
+		m.SetAuthority(map[ranker.Path]float64{"late": 1})
+		m.AddDocuments(map[string][]string{"late": {"generics"}})
+
+		assert.InDelta(t, 1, m.Docs["late"].Authority, 1e-9)
+		assert.Equal(t, ranker.Path("late"), m.Rank("generics")[0])
+	})
+}
@@ -0,0 +1,54 @@
+package ranker
+
+import (
+	"slices"
+	"sort"
+)
+
+// Query is a search query of a Model.
+type Query struct {
+	// Terms are key words of the query, they are split into terms by the analyzer of every document language.
+	Terms []string
+	// Language keeps only documents of the language, empty Language matches documents of all languages.
+	Language string
+}
+
+// Query returns paths of documents matched by the query sorted by the tf-idf rank.
+//
+// When AuthorityWeight is set, the document authority is added to the rank of matched documents.
+func (m *Model) Query(q Query) []Path {
+	terms := map[string][]string{}
+	docFreq := DocFreq{}
+	maxAuthority := m.maxAuthority()
+
+	for path, doc := range m.Docs {
+		if q.Language != "" && doc.Language != q.Language {
+			continue
+		}
+
+		if _, ok := terms[doc.Language]; !ok {
+			terms[doc.Language] = m.analyze(m.analyzer(doc.Language, true), q.Terms...)
+		}
+
+		rank := float64(0)
+		for _, term := range terms[doc.Language] {
+			rank += m.computeTFIDF(term, path)
+		}
+		if rank > 0 && maxAuthority > 0 {
+			rank += m.AuthorityWeight * doc.Authority / maxAuthority
+		}
+		docFreq[path] = rank
+	}
+
+	keys := make([]Path, 0, len(docFreq))
+	for key := range docFreq {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return docFreq[keys[i]] > docFreq[keys[j]] })
+
+	keys = slices.DeleteFunc(keys, func(path Path) bool {
+		return docFreq[path] <= 0
+	})
+
+	return keys
+}
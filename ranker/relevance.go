@@ -0,0 +1,35 @@
+package ranker
+
+import "math"
+
+// relevanceSaturation defines how fast a term relevance grows with its weighted count.
+// A single occurrence in the body gives ~0.4, a single occurrence in the title gives ~0.8.
+const relevanceSaturation = 0.5
+
+// Relevance returns how well the document by path matches keyWords, the result is in [0, 1).
+//
+// Unlike Rank, Relevance doesn't depend on other documents of the Model, so it can be used as a threshold,
+// e.g. to decide whether a page is about a topic. Every keyword adds 1 - e^(-0.5 * count) to the result,
+// where count is its count in the document weighted by FieldWeights, and the sum is averaged by keyword terms.
+func (m *Model) Relevance(path Path, keyWords ...string) float64 {
+	doc, ok := m.Docs[path]
+	terms := m.analyze(m.analyzer(doc.Language, true), keyWords...)
+	if !ok || len(terms) == 0 {
+		return 0
+	}
+
+	relevance := float64(0)
+	for _, term := range terms {
+		count := float64(0)
+		if len(doc.Fields) == 0 {
+			count = float64(doc.Terms[term])
+		}
+		for field, terms := range doc.Fields {
+			count += m.fieldWeight(field) * float64(terms[term])
+		}
+
+		relevance += 1 - math.Exp(-relevanceSaturation*count)
+	}
+
+	return relevance / float64(len(terms))
+}